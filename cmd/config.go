@@ -0,0 +1,65 @@
+package main
+
+import (
+	"blogSystem/config"
+	"blogSystem/pkg/database"
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+const configUsage = `usage: blog config <subcommand>
+
+subcommands:
+  check   校验配置、测试数据库连接并检查迁移状态`
+
+// runConfig 配置相关命令
+func runConfig(args []string) error {
+	action, args, err := subcommand(args, configUsage)
+	if err != nil {
+		return err
+	}
+	if action != "check" {
+		return fmt.Errorf("unknown subcommand %q\n%s", action, configUsage)
+	}
+	if err := newFlagSet("config check").Parse(args); err != nil {
+		return err
+	}
+
+	a, err := setup()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	printConfig(a.Config)
+
+	sqlDB, err := a.DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+
+	migrator, err := database.NewMigrator(a.DB)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nconfig: ok, database: ok, pending migrations: %d\n", pending)
+	return nil
+}
+
+// printConfig 输出全部生效的配置；DSN、JWT 密钥等敏感信息只显示是否已设置
+func printConfig(cfg *config.Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tENV\tVALUE")
+	for _, e := range cfg.Entries() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, e.Env, e.Value)
+	}
+	_ = w.Flush()
+}
@@ -2,52 +2,88 @@ package main
 
 import (
 	"blogSystem/config"
-	"blogSystem/internal/api"
-	"blogSystem/pkg/database"
-	"blogSystem/pkg/logger"
-
-	"go.uber.org/zap"
+	"blogSystem/internal/app"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
 )
 
-func main() {
-	// 第一阶段：基础日志初始化（用于配置加载期间的日志）
-	logger.Init("info")
-	defer logger.Sync()
+const usage = `usage: blog <command> [arguments]
 
-	// 加载配置
-	cfg, err := config.Load()
-	if err != nil {
-		logger.Fatal("Failed to load config", zap.Error(err))
+commands:
+  serve                          启动 HTTP 服务（默认）
+  migrate up|down|status|force-unlock
+                                 数据库迁移
+  user create|promote|reset-password|disable
+                                 用户账号管理
+  post reindex                   重建文章全文检索索引
+  seed -fake N                   生成 N 篇测试文章（含作者与评论）
+  config check                   校验配置并测试数据库连接
+
+所有子命令都支持 -config FILE（YAML/TOML 配置文件）与 -set key=value（覆盖单项配置），
+优先级：默认值 < 配置文件 < 环境变量 < -set。
+使用 "blog <command> -h" 查看子命令参数`
+
+// configFlags 由 newFlagSet 注册到每个子命令，setup 据此加载配置
+var configFlags config.Flags
+
+// commands 子命令表，各命令的实现位于同目录下的同名文件
+var commands = map[string]func(args []string) error{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"user":    runUser,
+	"post":    runPost,
+	"seed":    runSeed,
+	"config":  runConfig,
+}
+
+func main() {
+	// 不带子命令（或直接带参数）时执行 serve，兼容原来的启动方式
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	// 第二阶段：用配置中的设置重新初始化日志
-	logger.Init(cfg.Log.Level)
-	defer logger.Sync()
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", name, usage)
+		os.Exit(2)
+	}
+	if err := run(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
 
-	// 初始化数据库
-	if err := database.Init(cfg.DB.DSN); err != nil {
-		logger.Fatal("Database initialization failed",
-			zap.String("dsn", cfg.DB.DSN),
-			zap.Error(err),
-		)
+// setup 加载配置并创建应用容器（日志、数据库、令牌签发器），供各子命令共用；调用方负责 Close
+func setup() (*app.App, error) {
+	cfg, err := config.Load(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("load config:\n%w", err)
 	}
-	defer database.Close()
-
-	// 初始化HTTP服务器
-	router := api.NewRouter()
-	logger.Info("Server is starting",
-		zap.String("port", cfg.Server.Port),
-		zap.String("log_level", cfg.Log.Level),
-	)
-
-	// 启动服务器
-	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		logger.Fatal("Server startup failed",
-			zap.String("port", cfg.Server.Port),
-			zap.Error(err),
-		)
+	return app.New(cfg)
+}
+
+// subcommand 解析 "<group> <action> ..." 形式的参数，返回动作名与其余参数
+func subcommand(args []string, usage string) (string, []string, error) {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		fmt.Fprintln(os.Stderr, usage)
+		if len(args) == 0 {
+			return "", nil, errors.New("missing subcommand")
+		}
+		return "", nil, flag.ErrHelp
 	}
+	return args[0], args[1:], nil
+}
 
-	// 服务关闭
-	logger.Fatal("Server stopped", zap.Error(router.Run(":"+cfg.Server.Port)))
+// newFlagSet 创建子命令参数解析器并注册 -config/-set，解析失败时返回错误而不是直接退出
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFlags.Register(fs)
+	return fs
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"blogSystem/internal/app"
+	"blogSystem/pkg/database"
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const migrateUsage = `usage: blog migrate <subcommand>
+
+commands:
+  up             执行所有未执行的迁移
+  down [-steps N] 回滚最近 N 个迁移（默认 1）
+  status         查看迁移执行状态
+  force-unlock   强制释放迁移锁（持锁进程异常退出后使用）`
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(args []string) error {
+	action, args, err := subcommand(args, migrateUsage)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("migrate " + action)
+	steps := fs.Int("steps", 1, "number of migrations to revert (down only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := setup()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	migrator, err := database.NewMigrator(a.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch action {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+
+	case "down":
+		done, err := migrator.Down(ctx, *steps)
+		for _, m := range done {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, at := "pending", ""
+			if s.Applied {
+				state, at = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, at)
+		}
+		return w.Flush()
+
+	case "force-unlock":
+		return migrator.ForceUnlock(ctx)
+	}
+	return fmt.Errorf("unknown subcommand %q\n%s", action, migrateUsage)
+}
+
+// prepareSchema 启动服务前处理数据库迁移：
+// 开启 DB_MIGRATE_ON_START 时自动执行迁移；requireMigrated 为 true 时若仍有未执行的迁移则拒绝启动
+func prepareSchema(a *app.App, requireMigrated bool) error {
+	migrator, err := database.NewMigrator(a.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if a.Config.DB.MigrateOnStart {
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			a.Log.Info("Migration applied", zap.Int64("version", m.Version), zap.String("name", m.Name))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		if requireMigrated {
+			return fmt.Errorf("database schema is behind by %d migration(s); run `migrate up` first", pending)
+		}
+		a.Log.Warn("Database schema is behind", zap.Int("pending_migrations", pending))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	repogorm "blogSystem/internal/repository/gorm"
+	"blogSystem/internal/service"
+	"context"
+	"fmt"
+	"time"
+)
+
+const postUsage = `usage: blog post <subcommand>
+
+subcommands:
+  reindex   重建文章全文检索索引（批量导入数据后使用）`
+
+// runPost 文章维护命令
+func runPost(args []string) error {
+	action, args, err := subcommand(args, postUsage)
+	if err != nil {
+		return err
+	}
+	if action != "reindex" {
+		return fmt.Errorf("unknown subcommand %q\n%s", action, postUsage)
+	}
+	if err := newFlagSet("post reindex").Parse(args); err != nil {
+		return err
+	}
+
+	a, err := setup()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	start := time.Now()
+	postService := service.NewPostService(repogorm.NewPostRepository(a.DB))
+	if err := postService.Reindex(context.Background()); err != nil {
+		return err
+	}
+	fmt.Printf("reindex: ok (%s)\n", time.Since(start).Round(time.Millisecond))
+	return nil
+}
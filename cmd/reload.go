@@ -0,0 +1,112 @@
+package main
+
+import (
+	"blogSystem/config"
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// reloadDebounce 编辑器保存文件时往往连续产生多个事件，合并为一次重新加载
+const reloadDebounce = 300 * time.Millisecond
+
+// watchConfig 在配置文件变化或收到 SIGHUP 时重新加载配置，直到 ctx 结束
+func watchConfig(ctx context.Context, reloader *config.Reloader, log *zap.Logger) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var watcher *fsnotify.Watcher
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	file := reloader.File()
+	if file != "" {
+		var err error
+		if watcher, err = fsnotify.NewWatcher(); err != nil {
+			signal.Stop(hup)
+			return err
+		}
+		// 监听所在目录而不是文件本身：编辑器与 Kubernetes ConfigMap 通过重命名替换文件，
+		// 直接监听文件会在第一次替换后丢失后续事件
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			signal.Stop(hup)
+			_ = watcher.Close()
+			return err
+		}
+		events, watchErrs = watcher.Events, watcher.Errors
+	}
+
+	go func() {
+		defer signal.Stop(hup)
+		if watcher != nil {
+			defer watcher.Close()
+		}
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				reloadConfig(reloader, log, "SIGHUP")
+			case event := <-events:
+				if isConfigEvent(event, file) {
+					debounce = time.After(reloadDebounce)
+				}
+			case <-debounce:
+				debounce = nil
+				reloadConfig(reloader, log, "file")
+			case err := <-watchErrs:
+				log.Warn("Config file watch error", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// isConfigEvent 是否为配置文件本身的变化；Kubernetes 挂载的 ConfigMap 通过替换 ..data 符号链接更新
+func isConfigEvent(event fsnotify.Event, file string) bool {
+	if event.Has(fsnotify.Chmod) {
+		return false
+	}
+	name := filepath.Base(event.Name)
+	return name == filepath.Base(file) || name == "..data"
+}
+
+// reloadConfig 重新加载配置并逐项记录变化；失败时保持当前配置
+func reloadConfig(reloader *config.Reloader, log *zap.Logger, trigger string) {
+	changes, err := reloader.Reload()
+	if err != nil {
+		log.Error("Config reload rejected, keeping current settings",
+			zap.String("trigger", trigger),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(changes) == 0 {
+		log.Debug("Config reloaded, nothing changed", zap.String("trigger", trigger))
+		return
+	}
+	for _, change := range changes {
+		fields := []zap.Field{
+			zap.String("trigger", trigger),
+			zap.String("key", change.Key),
+			zap.String("old", change.Old),
+			zap.String("new", change.New),
+		}
+		switch {
+		case change.ShadowedBy != "":
+			log.Warn("Config file change has no effect, the setting is overridden",
+				append(fields, zap.String("overridden_by", change.ShadowedBy))...)
+		case change.Applied:
+			log.Info("Config setting reloaded", fields...)
+		default:
+			log.Warn("Config setting changed but requires a restart", fields...)
+		}
+	}
+}
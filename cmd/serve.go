@@ -0,0 +1,153 @@
+package main
+
+import (
+	"blogSystem/config"
+	"blogSystem/internal/api"
+	"blogSystem/pkg/database"
+	"blogSystem/pkg/health"
+	"blogSystem/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// shutdownStep 优雅关闭中的一步；各步骤按注册顺序依次执行，共享同一个截止时间
+type shutdownStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// runServe 启动 HTTP 服务，收到 SIGINT/SIGTERM 后优雅关闭
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	requireMigrated := fs.Bool("require-migrated", false, "refuse to start when database migrations are pending")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := setup()
+	if err != nil {
+		return err
+	}
+	// 启动失败时释放连接；正常退出时由关闭流程按顺序关闭（重复关闭无副作用），最后刷新日志
+	defer a.Close()
+	cfg, log := a.Config, a.Log
+
+	// 数据库迁移
+	if err := prepareSchema(a, *requireMigrated); err != nil {
+		return fmt.Errorf("database schema check failed: %w", err)
+	}
+
+	// 链路追踪：HTTP 请求、服务层与 GORM 语句的 span
+	flushTraces := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		tp, err := tracing.Init(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+		if err != nil {
+			return fmt.Errorf("initialize tracing: %w", err)
+		}
+		defer tp.Shutdown(context.Background())
+		if err := a.EnableTracing(tp); err != nil {
+			return err
+		}
+		flushTraces = tp.Shutdown
+	}
+
+	// 就绪检查：数据库连通、迁移已执行；其他子系统可继续向 readiness 注册
+	migrator, err := database.NewMigrator(a.DB)
+	if err != nil {
+		return err
+	}
+	readiness := health.NewRegistry(cfg.Health.CheckTimeout)
+	readiness.Register("database", func(ctx context.Context) error { return database.Ping(ctx, a.DB) })
+	readiness.Register("migrations", migrator.ReadyCheck())
+
+	// 配置热更新：日志级别在此订阅，其余由各服务在 NewRouter 中订阅
+	reloader := config.NewReloader(configFlags, cfg)
+	reloader.Subscribe(func(old, cfg *config.Config) {
+		if cfg.Log.Level != old.Log.Level {
+			_ = a.LogLevel.UnmarshalText([]byte(cfg.Log.Level)) // 已通过校验
+		}
+	})
+
+	// 初始化HTTP服务器
+	srv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           api.NewRouter(a, readiness, reloader),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := watchConfig(ctx, reloader, log); err != nil {
+		return fmt.Errorf("watch config file: %w", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info("Server is starting",
+			zap.String("port", cfg.Server.Port),
+			zap.String("log_level", cfg.Log.Level),
+		)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		// 未收到信号就退出说明监听失败（如端口被占用）
+		return fmt.Errorf("server startup failed on port %s: %w", cfg.Server.Port, err)
+	case <-ctx.Done():
+	}
+	// 恢复默认信号处理：关闭过程中再次按 Ctrl+C 会立即退出
+	stop()
+	log.Info("Shutdown signal received, draining connections",
+		zap.Duration("drain_delay", cfg.Server.DrainDelay),
+		zap.Duration("timeout", cfg.Server.ShutdownTimeout),
+	)
+
+	// 先让 /readyz 返回 503，等负载均衡摘除本实例后再停止接收请求；
+	// 这段时间内仍正常处理新请求
+	readiness.SetDraining()
+	time.Sleep(cfg.Server.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// 先停止接收新请求并等待进行中的请求完成，再导出剩余的 span，最后关闭数据库连接；
+	// 日志由 a.Close 在最后 Sync
+	return shutdown(shutdownCtx, log, []shutdownStep{
+		{"http server", srv.Shutdown},
+		{"tracing", flushTraces},
+		{"database", func(context.Context) error { return database.Close(a.DB) }},
+	})
+}
+
+// shutdown 依次执行关闭步骤；某一步失败或超时不影响后续步骤，最终返回所有错误
+func shutdown(ctx context.Context, log *zap.Logger, steps []shutdownStep) error {
+	var errs []error
+	for _, step := range steps {
+		start := time.Now()
+		if err := step.fn(ctx); err != nil {
+			log.Error("Shutdown step failed", zap.String("step", step.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		log.Info("Shutdown step completed",
+			zap.String("step", step.name),
+			zap.Duration("elapsed", time.Since(start)),
+		)
+	}
+	if len(errs) == 0 {
+		log.Info("Server stopped")
+	}
+	return errors.Join(errs...)
+}
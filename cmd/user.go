@@ -0,0 +1,101 @@
+package main
+
+import (
+	"blogSystem/internal/domain"
+	repogorm "blogSystem/internal/repository/gorm"
+	"blogSystem/internal/service"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const userUsage = `usage: blog user <subcommand> [flags]
+
+subcommands:
+  create         -username NAME -email EMAIL [-password PASS] [-role user|admin]
+  promote        -username NAME [-role admin]
+  reset-password -username NAME [-password PASS]
+  disable        -username NAME
+
+未指定 -password 时从标准输入读取一行作为密码，避免密码留在 shell 历史中`
+
+// runUser 用户账号管理，代替直接修改 users 表
+func runUser(args []string) error {
+	action, args, err := subcommand(args, userUsage)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("user " + action)
+	username := fs.String("username", "", "username")
+	var email, password, role *string
+	switch action {
+	case "create":
+		email = fs.String("email", "", "email address")
+		password = fs.String("password", "", "password (read from stdin when omitted)")
+		role = fs.String("role", domain.RoleUser, "role: user or admin")
+	case "promote":
+		role = fs.String("role", domain.RoleAdmin, "role: user or admin")
+	case "reset-password":
+		password = fs.String("password", "", "password (read from stdin when omitted)")
+	case "disable":
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", action, userUsage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+	if email != nil && *email == "" {
+		return errors.New("-email is required")
+	}
+	if password != nil && *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	a, err := setup()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	users := repogorm.NewUserRepository(a.DB)
+	userService := service.NewUserService(users)
+
+	var user *domain.User
+	switch action {
+	case "create":
+		user = &domain.User{Username: *username, Email: *email, Password: *password, Role: *role}
+		err = service.NewAuthService(users, a.Tokens).Register(ctx, user)
+	case "promote":
+		user, err = userService.SetRole(ctx, *username, *role)
+	case "reset-password":
+		user, err = userService.ResetPassword(ctx, *username, *password)
+	case "disable":
+		user, err = userService.Disable(ctx, *username)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: ok (id=%d username=%s role=%s disabled=%t)\n", action, user.ID, user.Username, user.Role, user.Disabled())
+	return nil
+}
+
+// readPassword 从标准输入读取一行作为密码
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -2,118 +2,419 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/joho/godotenv"
 )
 
+// Config 全部可调参数；每一项的配置文件键、环境变量名与默认值登记在 settings.go
 type Config struct {
 	DB struct {
-		DSN         string
-		MaxIdleConn int
-		MaxOpenConn int
+		Driver          string
+		DSN             string
+		MaxIdleConn     int
+		MaxOpenConn     int
+		ConnMaxLifetime time.Duration // 连接的最大存活时间，0 表示不限制
+		ConnMaxIdleTime time.Duration // 连接的最大空闲时间，0 表示不限制
+		MigrateOnStart  bool
+		LogLevel        string        // GORM 日志级别：silent、error、warn、info（info 时每条 SQL 以 debug 级别输出）
+		SlowThreshold   time.Duration // 超过该耗时的 SQL 以 warn 级别输出，0 表示不记录
 	}
 	JWT struct {
 		Secret   string
 		Lifetime time.Duration
 	}
+	Auth struct {
+		Registration string // open 或 closed；closed 时只能由管理员通过 user create 创建账号
+	}
 	Server struct {
-		Port string
-		Env  string
+		Port              string
+		Env               string
+		ReadTimeout       time.Duration // 读取完整请求（含请求体）的超时
+		ReadHeaderTimeout time.Duration // 读取请求头的超时，防止慢速攻击
+		WriteTimeout      time.Duration // 从读完请求头到写完响应的超时
+		IdleTimeout       time.Duration // keep-alive 空闲连接的保持时间
+		ShutdownTimeout   time.Duration // 优雅关闭时等待进行中请求完成的最长时间
+		DrainDelay        time.Duration // 收到关闭信号后 /readyz 先返回 503 的时长，留给负载均衡摘除实例
+		TrustedProxies    []string      // 信任其 X-Forwarded-For 的反向代理地址（IP 或 CIDR），为空时客户端 IP 取连接对端地址
 	}
 	Log struct {
-		Level string
+		Level              string
+		Format             string // json 或 console
+		Output             string // stdout 或 file
+		File               string
+		MaxSizeMB          int
+		MaxAgeDays         int
+		MaxBackups         int
+		Compress           bool
+		SamplingInitial    int
+		SamplingThereafter int
+	}
+	CORS struct {
+		AllowOrigins     []string      // 允许的前端来源，如 https://blog.example.com；为空时不处理跨域请求，* 表示任意来源
+		AllowMethods     []string      // 预检响应中允许的方法
+		AllowHeaders     []string      // 预检响应中允许的请求头
+		ExposeHeaders    []string      // 允许前端脚本读取的响应头
+		AllowCredentials bool          // 允许携带 Cookie；不能与 * 来源同时使用
+		MaxAge           time.Duration // 浏览器缓存预检结果的时间
+	}
+	Security struct {
+		CSP            string        // Content-Security-Policy，为空时不发送
+		HSTSMaxAge     time.Duration // 仅 production 环境发送 Strict-Transport-Security，0 表示不发送
+		ReferrerPolicy string        // Referrer-Policy，为空时不发送
+		CookieSessions bool          // 是否使用 Cookie 会话认证；启用后才挂载 CSRF 防护。当前 API 只通过 Authorization 头认证，默认关闭
+		SessionCookie  string        // Cookie 会话名：CookieSessions 启用时，携带该 Cookie 且没有 Authorization 头的请求需要 CSRF 令牌
+	}
+	HTTPCache struct {
+		DetailMaxAge  time.Duration // 文章详情的 Cache-Control max-age（仅匿名访问）
+		ListMaxAge    time.Duration // 文章列表、检索与评论列表的 max-age（仅匿名访问）
+		SitemapMaxAge time.Duration // sitemap 与 robots.txt 的 max-age
+	}
+	Pagination struct {
+		DefaultSize int // 未指定 size 时的每页条数
+		MaxSize     int // size 的上限
+	}
+	Health struct {
+		CheckTimeout time.Duration // 单个就绪检查的最长执行时间
+	}
+	Site struct {
+		BaseURL        string
+		RobotsDisallow []string
+		PostPath       string        // sitemap 中文章页面的路径模板，{id} 替换为文章 ID
+		AuthorPath     string        // sitemap 中作者页面的路径模板，为空时不输出作者页面
+		SitemapRefresh time.Duration // 检查其他实例或命令行是否写入了文章的间隔，0 表示只靠本进程的写操作更新
 	}
+	Metrics struct {
+		Enabled bool
+		Allow   []string // 允许直接访问 /metrics 的来源地址（IP 或 CIDR）
+		Token   string   // 可选：携带 Authorization: Bearer <Token> 的请求不受来源地址限制
+	}
+	RateLimit struct {
+		Enabled  bool
+		Store    string     // memory 或 redis；多实例部署时使用 redis 共享计数
+		RedisURL string     // store 为 redis 时的连接地址，如 redis://:password@localhost:6379/0
+		Auth     RatePolicy // 注册与登录，按客户端 IP 计数
+		Write    RatePolicy // 需要登录的写操作（发文、评论等），按用户计数
+		Read     RatePolicy // 公开的只读接口，已登录按用户、匿名按客户端 IP 计数
+	}
+	Cache struct {
+		Enabled    bool
+		Store      string        // memory 或 redis；多实例部署时使用 redis，写操作的失效对所有实例生效
+		RedisURL   string        // store 为 redis 时的连接地址
+		MaxEntries int           // store 为 memory 时的最大条目数，超过后淘汰最久未使用的项
+		TTL        time.Duration // 缓存项的存活时间，也是其他途径（如命令行）修改数据后缓存可能过时的最长时间
+	}
+	Tracing struct {
+		Enabled     bool
+		Exporter    string  // otlp 或 stdout；OTLP 端点由 OTEL_EXPORTER_OTLP_ENDPOINT 等标准环境变量配置
+		SampleRatio float64 // 采样比例 0～1，上游已采样的请求始终采样
+		ServiceName string
+	}
+
+	// 以下记录各项的来源，供重新加载时发现被覆盖而不生效的文件修改
+	fileValues   map[string]string // 配置文件中的取值，按配置键索引
+	overriddenBy map[string]string // 取值来自文件之上一层的配置项 → 来源（环境变量名、.env 或 -set）
 }
 
-func Load() (*Config, error) {
-	_ = godotenv.Load(".env", ".env.local")
-
-	cfg := &Config{
-		DB: struct {
-			DSN         string
-			MaxIdleConn int
-			MaxOpenConn int
-		}{
-			DSN:         getEnv("DB_DSN", ""),
-			MaxIdleConn: 10,
-			MaxOpenConn: 100,
-		},
-		JWT: struct {
-			Secret   string
-			Lifetime time.Duration
-		}{
-			Secret:   getEnv("JWT_SECRET", ""),
-			Lifetime: 24 * time.Hour,
-		},
-		Server: struct {
-			Port string
-			Env  string
-		}{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Env:  getEnv("SERVER_ENV", "development"),
-		},
-		Log: struct {
-			Level string
-		}{
-			Level: getEnv("LOG_LEVEL", "info"),
-		},
+// Load 按以下顺序叠加配置，后者覆盖前者：
+// 默认值 < 配置文件（-config 或 CONFIG_FILE）< .env < 进程环境变量 < 命令行 -set key=value。
+// .env 中的配置项每次加载都重新读取，不写入进程环境变量；其余变量（如 CONFIG_FILE、OTEL_*）
+// 在进程环境变量中没有同名变量时写入，供其他组件读取。
+// 任一层的解析错误与校验错误会一并返回，而不是只报第一个
+func Load(flags Flags) (*Config, error) {
+	var errs []error
+	dotenv, err := readDotenv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg := &Config{overriddenBy: make(map[string]string)}
+	settings := cfg.settings()
+	for _, s := range settings {
+		if err := s.set(s.def); err != nil {
+			panic(fmt.Sprintf("config: invalid default for %s: %v", s.key, err))
+		}
+	}
+
+	known := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		known[s.env] = true
+	}
+	for key, value := range dotenv {
+		if _, set := os.LookupEnv(key); !set && !known[key] {
+			_ = os.Setenv(key, value)
+		}
+	}
+
+	file := flags.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			// 文件无法读取时其余各项的校验结果没有参考意义，直接返回
+			return nil, err
+		}
+		errs = append(errs, apply(settings, values, file)...)
+		cfg.fileValues = values
+	}
+
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		source := s.env
+		if !ok {
+			value, ok = dotenv[s.env]
+			source = ".env " + s.env
+		}
+		if !ok {
+			continue
+		}
+		if err := s.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+		}
+		cfg.overriddenBy[s.key] = source
+	}
+
+	overrides, err := flags.overrides()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, apply(settings, overrides, "-set")...)
+	for key := range overrides {
+		cfg.overriddenBy[key] = "-set"
 	}
 
 	if err := cfg.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
 		return nil, err
 	}
 	return cfg, nil
 }
 
+// validate 检查各项取值，返回全部问题
 func (c *Config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
 	// 验证数据库配置
-	if c.DB.DSN == "" {
-		return errors.New("database DSN configuration is required")
+	check(c.DB.Driver == "mysql" || c.DB.Driver == "postgres" || c.DB.Driver == "sqlite",
+		"db.driver must be one of: mysql, postgres, sqlite")
+	check(c.DB.DSN != "", "db.dsn is required")
+	check(c.DB.MaxOpenConn > 0, "db.max_open_conns must be positive")
+	check(c.DB.MaxIdleConn >= 0, "db.max_idle_conns must not be negative") // 超过 max_open_conns 时由 database/sql 自动收紧
+	check(c.DB.ConnMaxLifetime >= 0, "db.conn_max_lifetime must not be negative")
+	check(c.DB.ConnMaxIdleTime >= 0, "db.conn_max_idle_time must not be negative")
+	switch c.DB.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		errs = append(errs, errors.New("db.log_level must be one of: silent, error, warn, info"))
 	}
+	check(c.DB.SlowThreshold >= 0, "db.slow_threshold must not be negative")
 
 	// 验证JWT配置
 	if c.JWT.Secret == "" {
-		return errors.New("JWT secret key is required")
-	}
-	if len(c.JWT.Secret) < 32 {
-		return errors.New("JWT secret must be at least 32 characters long")
+		errs = append(errs, errors.New("jwt.secret is required"))
+	} else {
+		check(len(c.JWT.Secret) >= 32, "jwt.secret must be at least 32 characters long")
 	}
+	check(c.JWT.Lifetime > 0, "jwt.lifetime must be a positive duration such as 24h")
+	check(c.Auth.Registration == "open" || c.Auth.Registration == "closed", "auth.registration must be either 'open' or 'closed'")
 
 	// 验证服务器配置
-	if c.Server.Port == "" {
-		return errors.New("server port is required")
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 0 || port > 65535 {
+		errs = append(errs, errors.New("server.port must be a port number"))
 	}
-	if _, err := strconv.Atoi(c.Server.Port); err != nil {
-		return errors.New("server port must be a valid number")
+	check(c.Server.Env == "development" || c.Server.Env == "production",
+		"server.env must be either 'development' or 'production'")
+	for _, timeout := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"health.check_timeout", c.Health.CheckTimeout},
+	} {
+		check(timeout.value > 0, "%s must be a positive duration such as 15s", timeout.key)
 	}
-	if c.Server.Env != "development" && c.Server.Env != "production" {
-		return errors.New("server environment must be either 'development' or 'production'")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay must not be negative")
+
+	if _, err := ParsePrefixes(c.Server.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("server.trusted_proxies: %w", err))
+	}
+
+	// 验证日志配置
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error", "dpanic", "panic", "fatal":
+	default:
+		errs = append(errs, errors.New("log.level must be one of: debug, info, warn, error, dpanic, panic, fatal"))
+	}
+	check(c.Log.Format == "json" || c.Log.Format == "console", "log.format must be either 'json' or 'console'")
+	switch c.Log.Output {
+	case "stdout":
+	case "file":
+		check(c.Log.File != "", "log.file is required when log.output is 'file'")
+		check(c.Log.MaxSizeMB > 0, "log.max_size_mb must be positive")
+		check(c.Log.MaxAgeDays >= 0, "log.max_age_days must not be negative")
+		check(c.Log.MaxBackups >= 0, "log.max_backups must not be negative")
+	default:
+		errs = append(errs, errors.New("log.output must be either 'stdout' or 'file'"))
+	}
+	check(c.Log.SamplingInitial >= 0 && c.Log.SamplingThereafter >= 0,
+		"log.sampling_initial and log.sampling_thereafter must not be negative")
+
+	// 验证跨域配置
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" {
+			check(!c.CORS.AllowCredentials, "cors.allow_origins must not contain '*' when cors.allow_credentials is true")
+			continue
+		}
+		u, err := url.Parse(origin)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "" && u.RawQuery == "",
+			"cors.allow_origins: %q must be '*' or an origin such as https://blog.example.com", origin)
+	}
+	check(c.CORS.MaxAge >= 0, "cors.max_age must not be negative")
+	check(c.Security.HSTSMaxAge >= 0, "security.hsts_max_age must not be negative")
+	check(!c.Security.CookieSessions || c.Security.SessionCookie != "", "security.session_cookie is required when security.cookie_sessions is true")
+
+	check(c.HTTPCache.DetailMaxAge >= 0 && c.HTTPCache.ListMaxAge >= 0 && c.HTTPCache.SitemapMaxAge >= 0,
+		"http_cache max ages must not be negative")
+
+	// 验证分页配置
+	check(c.Pagination.MaxSize > 0, "pagination.max_size must be positive")
+	check(c.Pagination.DefaultSize > 0 && c.Pagination.DefaultSize <= c.Pagination.MaxSize,
+		"pagination.default_size must be between 1 and pagination.max_size")
+
+	// 验证站点配置
+	check(strings.HasPrefix(c.Site.BaseURL, "http://") || strings.HasPrefix(c.Site.BaseURL, "https://"),
+		"site.base_url must start with http:// or https://")
+	check(strings.HasPrefix(c.Site.PostPath, "/") && strings.Contains(c.Site.PostPath, "{id}"),
+		"site.post_path must start with / and contain {id}")
+	check(c.Site.AuthorPath == "" || strings.HasPrefix(c.Site.AuthorPath, "/") && strings.Contains(c.Site.AuthorPath, "{id}"),
+		"site.author_path must be empty or start with / and contain {id}")
+	check(c.Site.SitemapRefresh >= 0, "site.sitemap_refresh must not be negative")
+
+	// 验证监控配置
+	if _, err := ParsePrefixes(c.Metrics.Allow); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.allow: %w", err))
+	}
+
+	// 验证限流配置
+	switch c.RateLimit.Store {
+	case "memory":
+	case "redis":
+		check(c.RateLimit.RedisURL != "", "ratelimit.redis_url is required when ratelimit.store is 'redis'")
+	default:
+		errs = append(errs, errors.New("ratelimit.store must be either 'memory' or 'redis'"))
+	}
+
+	// 验证缓存配置
+	switch c.Cache.Store {
+	case "memory":
+		check(c.Cache.MaxEntries > 0, "cache.max_entries must be positive")
+	case "redis":
+		check(c.Cache.RedisURL != "", "cache.redis_url is required when cache.store is 'redis'")
+	default:
+		errs = append(errs, errors.New("cache.store must be either 'memory' or 'redis'"))
 	}
+	check(c.Cache.TTL > 0, "cache.ttl must be a positive duration such as 5m")
 
-	// 验证日志级别
-	validLogLevels := map[string]bool{
-		"debug":  true,
-		"info":   true,
-		"warn":   true,
-		"error":  true,
-		"dpanic": true,
-		"panic":  true,
-		"fatal":  true,
+	// 验证链路追踪配置
+	check(c.Tracing.Exporter == "otlp" || c.Tracing.Exporter == "stdout", "tracing.exporter must be one of: otlp, stdout")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be a number between 0 and 1")
+
+	return errors.Join(errs...)
+}
+
+// ParsePrefixes 解析 IP 或 CIDR 列表，单个 IP 视为只包含该地址的网段
+func ParsePrefixes(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, item := range list {
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// RatePolicy 一组路由的限流策略：每 Period 允许 Requests 个请求，最多突发 Burst 个；Requests 为 0 表示不限流
+type RatePolicy struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// ParseRatePolicy 解析 "requests/period[:burst]" 形式的策略，如 "10/1m"、"5/s:20"；
+// period 只写单位时视为 1 个单位，burst 省略时等于 requests，"off" 表示不限流
+func ParseRatePolicy(s string) (RatePolicy, error) {
+	if s == "" || strings.EqualFold(s, "off") {
+		return RatePolicy{}, nil
 	}
-	if !validLogLevels[strings.ToLower(c.Log.Level)] {
-		return errors.New("invalid log level, must be one of: debug, info, warn, error, dpanic, panic, fatal")
+	spec, burst, hasBurst := strings.Cut(s, ":")
+	requests, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RatePolicy{}, fmt.Errorf("invalid rate %q, expected requests/period such as 10/1m", s)
 	}
 
-	return nil
+	var p RatePolicy
+	var err error
+	if p.Requests, err = strconv.Atoi(requests); err != nil || p.Requests <= 0 {
+		return RatePolicy{}, fmt.Errorf("invalid request count in rate %q", s)
+	}
+	if period != "" && strings.IndexAny(period[:1], "0123456789") < 0 {
+		period = "1" + period
+	}
+	if p.Period, err = time.ParseDuration(period); err != nil || p.Period <= 0 {
+		return RatePolicy{}, fmt.Errorf("invalid period in rate %q", s)
+	}
+	p.Burst = p.Requests
+	if hasBurst {
+		if p.Burst, err = strconv.Atoi(burst); err != nil || p.Burst <= 0 {
+			return RatePolicy{}, fmt.Errorf("invalid burst in rate %q", s)
+		}
+	}
+	return p, nil
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// String 与 ParseRatePolicy 互逆
+func (p RatePolicy) String() string {
+	if p.Requests == 0 {
+		return "off"
+	}
+	// 1m0s、1h0m0s 之类的时长省略末尾的零
+	period := p.Period.String()
+	if strings.HasSuffix(period, "m0s") {
+		period = strings.TrimSuffix(period, "0s")
+	}
+	if strings.HasSuffix(period, "h0m") {
+		period = strings.TrimSuffix(period, "0m")
+	}
+	s := strconv.Itoa(p.Requests) + "/" + period
+	if p.Burst != p.Requests {
+		s += ":" + strconv.Itoa(p.Burst)
 	}
-	return fallback
+	return s
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Change 重新加载时一项配置的变化；敏感项只显示是否已设置
+type Change struct {
+	Key     string
+	Old     string
+	New     string
+	Applied bool // false 表示该项不支持热更新，需重启生效
+	// ShadowedBy 非空时表示配置文件中可热更新的项被修改，但该项由环境变量、.env 或 -set 决定，
+	// 修改没有生效；此时 Old、New 为文件中修改前后的取值
+	ShadowedBy string
+}
+
+// Reloader 重新加载配置，并把可热更新的项交给订阅者应用到运行中的服务
+type Reloader struct {
+	flags Flags
+
+	mu          sync.Mutex
+	current     *Config
+	subscribers []func(old, cfg *Config)
+}
+
+// NewReloader cfg 为启动时生效的配置，flags 与启动时相同，保证重新加载时的叠加顺序一致
+func NewReloader(flags Flags, cfg *Config) *Reloader {
+	return &Reloader{flags: flags, current: cfg}
+}
+
+// File 配置文件路径；未使用配置文件时为空，此时只能通过 SIGHUP 重新读取环境变量
+func (r *Reloader) File() string {
+	if r.flags.File != "" {
+		return r.flags.File
+	}
+	return os.Getenv("CONFIG_FILE")
+}
+
+// Subscribe 注册热更新回调，在有可热更新的项发生变化时调用；回调应只读取自己关心的项
+func (r *Reloader) Subscribe(fn func(old, cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Reload 重新加载并校验配置，通过后替换可热更新的项并通知订阅者；
+// 加载或校验失败时返回错误，运行中的配置保持不变
+func (r *Reloader) Reload() ([]Change, error) {
+	next, err := Load(r.flags)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.current
+	merged, changes := old.merge(next)
+	if err := merged.validate(); err != nil {
+		return nil, err
+	}
+	changes = append(changes, old.shadowed(next)...)
+
+	// 即使没有可热更新的项变化也替换：merged 带有本次加载的来源记录
+	r.current = merged
+	for _, change := range changes {
+		if change.Applied {
+			for _, fn := range r.subscribers {
+				fn(old, merged)
+			}
+			break
+		}
+	}
+	return changes, nil
+}
+
+// merge 以 c 为基础取 next 中可热更新的项，返回合并后的配置与全部变化（含不可热更新的项）
+func (c *Config) merge(next *Config) (*Config, []Change) {
+	merged := *c
+	merged.fileValues, merged.overriddenBy = next.fileValues, next.overriddenBy
+	var changes []Change
+	nextSettings := next.settings()
+	for i, s := range merged.settings() {
+		n := nextSettings[i]
+		if s.get() == n.get() {
+			continue
+		}
+		changes = append(changes, Change{Key: s.key, Old: s.display(), New: n.display(), Applied: s.hot})
+		if s.hot {
+			if err := s.set(n.get()); err != nil {
+				panic(fmt.Sprintf("config: reload %s: %v", s.key, err))
+			}
+		}
+	}
+	return &merged, changes
+}
+
+// shadowed 找出配置文件中已修改、但被更高优先级的来源覆盖的可热更新项
+func (c *Config) shadowed(next *Config) []Change {
+	var changes []Change
+	for _, s := range next.settings() {
+		if !s.hot {
+			continue
+		}
+		by, ok := next.overriddenBy[s.key]
+		if !ok {
+			continue
+		}
+		old, oldSet := c.fileValues[s.key]
+		value, set := next.fileValues[s.key]
+		if old == value && oldSet == set {
+			continue
+		}
+		if s.secret {
+			old, value = "(changed)", "(changed)"
+		}
+		changes = append(changes, Change{Key: s.key, Old: old, New: value, ShadowedBy: by})
+	}
+	return changes
+}
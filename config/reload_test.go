@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile 写入 dir 下的文件，返回其路径
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const baseYAML = `
+db:
+  driver: sqlite
+  dsn: ":memory:"
+jwt:
+  secret: ssssssssssssssssssssssssssssssss
+`
+
+// TestReloadReportsShadowedFileChange 文件中修改的热更新项被环境变量覆盖时，不生效但要报告出来
+func TestReloadReportsShadowedFileChange(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := writeFile(t, dir, "config.yaml", baseYAML+"log:\n  level: info\n")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	flags := Flags{File: file}
+	cfg, err := Load(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader := NewReloader(flags, cfg)
+
+	writeFile(t, dir, "config.yaml", baseYAML+"log:\n  level: warn\n")
+	changes, err := reloader.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Key != "log.level" || changes[0].ShadowedBy != "LOG_LEVEL" ||
+		changes[0].Old != "info" || changes[0].New != "warn" {
+		t.Fatalf("expected a shadowed log.level change, got %+v", changes)
+	}
+	if got := reloader.current.Log.Level; got != "debug" {
+		t.Fatalf("environment must keep precedence, log.level is %q", got)
+	}
+
+	// 文件没有再变化时不重复报告
+	if changes, err := reloader.Reload(); err != nil || len(changes) != 0 {
+		t.Fatalf("expected no changes on an unchanged reload, got %+v (%v)", changes, err)
+	}
+}
+
+// TestReloadRereadsDotenv .env 每次加载都重新读取，且不写入进程环境变量
+func TestReloadRereadsDotenv(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := writeFile(t, dir, "config.yaml", baseYAML)
+	writeFile(t, dir, ".env", "AUTH_REGISTRATION=open\nOTEL_TEST_ONLY_VAR=x\n")
+	t.Setenv("OTEL_TEST_ONLY_VAR", "") // 测试结束时恢复原状
+	_ = os.Unsetenv("OTEL_TEST_ONLY_VAR")
+
+	flags := Flags{File: file}
+	cfg, err := Load(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, set := os.LookupEnv("AUTH_REGISTRATION"); set {
+		t.Fatal(".env settings must not leak into the process environment")
+	}
+	if os.Getenv("OTEL_TEST_ONLY_VAR") != "x" {
+		t.Fatal(".env variables that are not settings must be exported for other components")
+	}
+
+	reloader := NewReloader(flags, cfg)
+	writeFile(t, dir, ".env", "AUTH_REGISTRATION=closed\n")
+	changes, err := reloader.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || !changes[0].Applied || changes[0].New != "closed" {
+		t.Fatalf("expected auth.registration to reload from .env, got %+v", changes)
+	}
+}
+
+// TestLoadReportsDotenvSource .env 中的非法取值在错误中指明来源
+func TestLoadReportsDotenvSource(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := writeFile(t, dir, "config.yaml", baseYAML)
+	writeFile(t, dir, ".env", "SERVER_DRAIN_DELAY=soon\n")
+
+	_, err := Load(Flags{File: file})
+	if err == nil || !strings.Contains(err.Error(), ".env SERVER_DRAIN_DELAY") {
+		t.Fatalf("expected an error naming .env SERVER_DRAIN_DELAY, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting 一项配置：配置文件与 -set 使用的键、对应的环境变量及默认值
+type setting struct {
+	key    string
+	env    string
+	def    string
+	secret bool // 输出配置时隐藏取值
+	hot    bool // 支持运行时热更新，见 Reloader
+	set    func(string) error
+	get    func() string
+}
+
+// settings 登记全部配置项，新增配置时只需在此处添加一行；
+// 用 hot 标记的项在配置文件变化或收到 SIGHUP 时热更新，其余项修改后需重启生效
+func (c *Config) settings() []setting {
+	return []setting{
+		lowerVar(&c.DB.Driver, "db.driver", "DB_DRIVER", "mysql"),
+		secret(stringVar(&c.DB.DSN, "db.dsn", "DB_DSN", "")),
+		intVar(&c.DB.MaxIdleConn, "db.max_idle_conns", "DB_MAX_IDLE_CONNS", "10"),
+		intVar(&c.DB.MaxOpenConn, "db.max_open_conns", "DB_MAX_OPEN_CONNS", "100"),
+		durationVar(&c.DB.ConnMaxLifetime, "db.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", "1h"),
+		durationVar(&c.DB.ConnMaxIdleTime, "db.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", "30m"),
+		boolVar(&c.DB.MigrateOnStart, "db.migrate_on_start", "DB_MIGRATE_ON_START", "true"),
+		lowerVar(&c.DB.LogLevel, "db.log_level", "DB_LOG_LEVEL", "warn"),
+		durationVar(&c.DB.SlowThreshold, "db.slow_threshold", "DB_SLOW_THRESHOLD", "200ms"),
+
+		secret(stringVar(&c.JWT.Secret, "jwt.secret", "JWT_SECRET", "")),
+		durationVar(&c.JWT.Lifetime, "jwt.lifetime", "JWT_LIFETIME", "24h"),
+
+		hot(lowerVar(&c.Auth.Registration, "auth.registration", "AUTH_REGISTRATION", "open")),
+
+		stringVar(&c.Server.Port, "server.port", "SERVER_PORT", "8080"),
+		stringVar(&c.Server.Env, "server.env", "SERVER_ENV", "development"),
+		durationVar(&c.Server.ReadTimeout, "server.read_timeout", "SERVER_READ_TIMEOUT", "15s"),
+		durationVar(&c.Server.ReadHeaderTimeout, "server.read_header_timeout", "SERVER_READ_HEADER_TIMEOUT", "5s"),
+		durationVar(&c.Server.WriteTimeout, "server.write_timeout", "SERVER_WRITE_TIMEOUT", "30s"),
+		durationVar(&c.Server.IdleTimeout, "server.idle_timeout", "SERVER_IDLE_TIMEOUT", "60s"),
+		durationVar(&c.Server.ShutdownTimeout, "server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", "20s"),
+		durationVar(&c.Server.DrainDelay, "server.drain_delay", "SERVER_DRAIN_DELAY", "5s"),
+		listVar(&c.Server.TrustedProxies, "server.trusted_proxies", "SERVER_TRUSTED_PROXIES", ""),
+
+		hot(lowerVar(&c.Log.Level, "log.level", "LOG_LEVEL", "info")),
+		lowerVar(&c.Log.Format, "log.format", "LOG_FORMAT", "json"),
+		lowerVar(&c.Log.Output, "log.output", "LOG_OUTPUT", "stdout"),
+		stringVar(&c.Log.File, "log.file", "LOG_FILE", "logs/blog.log"),
+		intVar(&c.Log.MaxSizeMB, "log.max_size_mb", "LOG_MAX_SIZE_MB", "100"),
+		intVar(&c.Log.MaxAgeDays, "log.max_age_days", "LOG_MAX_AGE_DAYS", "7"),
+		intVar(&c.Log.MaxBackups, "log.max_backups", "LOG_MAX_BACKUPS", "10"),
+		boolVar(&c.Log.Compress, "log.compress", "LOG_COMPRESS", "true"),
+		intVar(&c.Log.SamplingInitial, "log.sampling_initial", "LOG_SAMPLING_INITIAL", "0"),
+		intVar(&c.Log.SamplingThereafter, "log.sampling_thereafter", "LOG_SAMPLING_THEREAFTER", "100"),
+
+		listVar(&c.CORS.AllowOrigins, "cors.allow_origins", "CORS_ALLOW_ORIGINS", ""),
+		listVar(&c.CORS.AllowMethods, "cors.allow_methods", "CORS_ALLOW_METHODS", "GET,POST,PUT,PATCH,DELETE"),
+		listVar(&c.CORS.AllowHeaders, "cors.allow_headers", "CORS_ALLOW_HEADERS",
+			"Authorization,Content-Type,Accept-Language,X-Request-ID"),
+		listVar(&c.CORS.ExposeHeaders, "cors.expose_headers", "CORS_EXPOSE_HEADERS",
+			"X-Request-ID,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset,Retry-After,Content-Language,Deprecation,Sunset,Link"),
+		boolVar(&c.CORS.AllowCredentials, "cors.allow_credentials", "CORS_ALLOW_CREDENTIALS", "false"),
+		durationVar(&c.CORS.MaxAge, "cors.max_age", "CORS_MAX_AGE", "12h"),
+
+		stringVar(&c.Security.CSP, "security.csp", "SECURITY_CSP", "default-src 'none'; frame-ancestors 'none'"),
+		durationVar(&c.Security.HSTSMaxAge, "security.hsts_max_age", "SECURITY_HSTS_MAX_AGE", "8760h"),
+		stringVar(&c.Security.ReferrerPolicy, "security.referrer_policy", "SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+		boolVar(&c.Security.CookieSessions, "security.cookie_sessions", "SECURITY_COOKIE_SESSIONS", "false"),
+		stringVar(&c.Security.SessionCookie, "security.session_cookie", "SECURITY_SESSION_COOKIE", "session"),
+
+		durationVar(&c.HTTPCache.DetailMaxAge, "http_cache.detail_max_age", "HTTP_CACHE_DETAIL_MAX_AGE", "1m"),
+		durationVar(&c.HTTPCache.ListMaxAge, "http_cache.list_max_age", "HTTP_CACHE_LIST_MAX_AGE", "30s"),
+		durationVar(&c.HTTPCache.SitemapMaxAge, "http_cache.sitemap_max_age", "HTTP_CACHE_SITEMAP_MAX_AGE", "1h"),
+
+		intVar(&c.Pagination.DefaultSize, "pagination.default_size", "PAGINATION_DEFAULT_SIZE", "10"),
+		intVar(&c.Pagination.MaxSize, "pagination.max_size", "PAGINATION_MAX_SIZE", "100"),
+
+		durationVar(&c.Health.CheckTimeout, "health.check_timeout", "HEALTH_CHECK_TIMEOUT", "2s"),
+
+		urlVar(&c.Site.BaseURL, "site.base_url", "SITE_BASE_URL", "http://localhost:8080"),
+		listVar(&c.Site.RobotsDisallow, "site.robots_disallow", "ROBOTS_DISALLOW", ""),
+		stringVar(&c.Site.PostPath, "site.post_path", "SITE_POST_PATH", "/api/v1/posts/{id}"),
+		stringVar(&c.Site.AuthorPath, "site.author_path", "SITE_AUTHOR_PATH", "/api/v1/users/{id}/posts"),
+		durationVar(&c.Site.SitemapRefresh, "site.sitemap_refresh", "SITEMAP_REFRESH", "5m"),
+
+		boolVar(&c.Metrics.Enabled, "metrics.enabled", "METRICS_ENABLED", "true"),
+		listVar(&c.Metrics.Allow, "metrics.allow", "METRICS_ALLOW", "127.0.0.1,::1"),
+		secret(stringVar(&c.Metrics.Token, "metrics.token", "METRICS_TOKEN", "")),
+
+		boolVar(&c.RateLimit.Enabled, "ratelimit.enabled", "RATELIMIT_ENABLED", "true"),
+		lowerVar(&c.RateLimit.Store, "ratelimit.store", "RATELIMIT_STORE", "memory"),
+		secret(stringVar(&c.RateLimit.RedisURL, "ratelimit.redis_url", "RATELIMIT_REDIS_URL", "")),
+		hot(policyVar(&c.RateLimit.Auth, "ratelimit.auth", "RATELIMIT_AUTH", "10/1m")),
+		hot(policyVar(&c.RateLimit.Write, "ratelimit.write", "RATELIMIT_WRITE", "30/1m")),
+		hot(policyVar(&c.RateLimit.Read, "ratelimit.read", "RATELIMIT_READ", "300/1m:60")),
+
+		boolVar(&c.Cache.Enabled, "cache.enabled", "CACHE_ENABLED", "true"),
+		lowerVar(&c.Cache.Store, "cache.store", "CACHE_STORE", "memory"),
+		secret(stringVar(&c.Cache.RedisURL, "cache.redis_url", "CACHE_REDIS_URL", "")),
+		intVar(&c.Cache.MaxEntries, "cache.max_entries", "CACHE_MAX_ENTRIES", "10000"),
+		durationVar(&c.Cache.TTL, "cache.ttl", "CACHE_TTL", "5m"),
+
+		boolVar(&c.Tracing.Enabled, "tracing.enabled", "TRACING_ENABLED", "false"),
+		lowerVar(&c.Tracing.Exporter, "tracing.exporter", "TRACING_EXPORTER", "otlp"),
+		floatVar(&c.Tracing.SampleRatio, "tracing.sample_ratio", "TRACING_SAMPLE_RATIO", "1"),
+		stringVar(&c.Tracing.ServiceName, "tracing.service_name", "OTEL_SERVICE_NAME", "blogSystem"),
+	}
+}
+
+// Entry 一项生效的配置，供 config check 输出；敏感项只显示是否已设置
+type Entry struct {
+	Key   string
+	Env   string
+	Value string
+}
+
+// Entries 按登记顺序返回全部配置项的当前取值
+func (c *Config) Entries() []Entry {
+	settings := c.settings()
+	entries := make([]Entry, 0, len(settings))
+	for _, s := range settings {
+		entries = append(entries, Entry{Key: s.key, Env: s.env, Value: s.display()})
+	}
+	return entries
+}
+
+// display 输出用的取值，敏感项只显示是否已设置
+func (s setting) display() string {
+	if !s.secret {
+		return s.get()
+	}
+	if s.get() == "" {
+		return "(unset)"
+	}
+	return "(set)"
+}
+
+func bind[T any](p *T, key, env, def string, parse func(string) (T, error), format func(T) string) setting {
+	return setting{
+		key: key,
+		env: env,
+		def: def,
+		set: func(s string) error {
+			v, err := parse(strings.TrimSpace(s))
+			if err != nil {
+				return err
+			}
+			*p = v
+			return nil
+		},
+		get: func() string { return format(*p) },
+	}
+}
+
+func secret(s setting) setting {
+	s.secret = true
+	return s
+}
+
+func hot(s setting) setting {
+	s.hot = true
+	return s
+}
+
+func stringVar(p *string, key, env, def string) setting {
+	return bind(p, key, env, def, func(s string) (string, error) { return s, nil }, identity)
+}
+
+// lowerVar 取值不区分大小写的枚举项
+func lowerVar(p *string, key, env, def string) setting {
+	return bind(p, key, env, def, func(s string) (string, error) { return strings.ToLower(s), nil }, identity)
+}
+
+// urlVar 站点地址，去掉末尾的斜杠便于拼接路径
+func urlVar(p *string, key, env, def string) setting {
+	return bind(p, key, env, def, func(s string) (string, error) { return strings.TrimRight(s, "/"), nil }, identity)
+}
+
+func intVar(p *int, key, env, def string) setting {
+	return bind(p, key, env, def, strconv.Atoi, strconv.Itoa)
+}
+
+func boolVar(p *bool, key, env, def string) setting {
+	return bind(p, key, env, def, strconv.ParseBool, strconv.FormatBool)
+}
+
+func floatVar(p *float64, key, env, def string) setting {
+	return bind(p, key, env, def,
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 64) },
+		func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) })
+}
+
+// durationVar 时长，如 15s、1m、24h
+func durationVar(p *time.Duration, key, env, def string) setting {
+	return bind(p, key, env, def, time.ParseDuration, time.Duration.String)
+}
+
+// policyVar 限流策略，格式见 ParseRatePolicy
+func policyVar(p *RatePolicy, key, env, def string) setting {
+	return bind(p, key, env, def, ParseRatePolicy, RatePolicy.String)
+}
+
+// listVar 以逗号分隔的列表，忽略空项
+func listVar(p *[]string, key, env, def string) setting {
+	return bind(p, key, env, def, func(s string) ([]string, error) {
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}, func(items []string) string { return strings.Join(items, ",") })
+}
+
+func identity(s string) string { return s }
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags 命令行中的配置参数，由各子命令注册到自己的 FlagSet
+type Flags struct {
+	File string   // -config：配置文件路径，未指定时读取 CONFIG_FILE
+	Set  []string // -set key=value，可重复
+}
+
+// Register 在 fs 上注册 -config 与 -set
+func (f *Flags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.File, "config", "", "config file (.yaml, .yml or .toml); defaults to $CONFIG_FILE")
+	fs.Func("set", "override a config key, e.g. -set server.port=9090 (repeatable)", func(s string) error {
+		if !strings.Contains(s, "=") {
+			return errors.New("expected key=value")
+		}
+		f.Set = append(f.Set, s)
+		return nil
+	})
+}
+
+func (f Flags) overrides() (map[string]string, error) {
+	values := make(map[string]string, len(f.Set))
+	var errs []error
+	for _, s := range f.Set {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("-set %q: expected key=value", s))
+			continue
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, errors.Join(errs...)
+}
+
+// dotenvFiles 工作目录下的 .env 文件，同名变量以先列出的文件为准（与 godotenv.Load 一致）
+var dotenvFiles = []string{".env", ".env.local"}
+
+// readDotenv 读取 dotenvFiles，不存在的文件跳过。每次加载配置都重新读取，
+// 修改 .env 后发送 SIGHUP 即可生效
+func readDotenv() (map[string]string, error) {
+	values := make(map[string]string)
+	for _, path := range dotenvFiles {
+		file, err := godotenv.Read(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for key, value := range file {
+			if _, ok := values[key]; !ok {
+				values[key] = value
+			}
+		}
+	}
+	return values, nil
+}
+
+// readFile 读取 YAML 或 TOML 配置文件，按扩展名区分格式，返回以点号连接的键，如 server.port
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	var tree map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", tree, values)
+	return values, nil
+}
+
+// flatten 把嵌套的表展开为点号连接的键；列表以逗号连接，与环境变量的写法一致
+func flatten(prefix string, tree map[string]any, out map[string]string) {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// apply 把 values 写入对应的配置项；source 用于错误信息，未登记的键视为错误
+func apply(settings []setting, values map[string]string, source string) []error {
+	var errs []error
+	known := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		known[s.key] = true
+		value, ok := values[s.key]
+		if !ok {
+			continue
+		}
+		if err := s.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", source, s.key, err))
+		}
+	}
+
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("%s: unknown key %q", source, key))
+	}
+	return errs
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"blogSystem/internal/api/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postWithCookies 不带 Authorization 头创建文章，模拟浏览器自动附带 Cookie 的跨站请求
+func postWithCookies(r *gin.Engine, csrfHeader string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/posts", strings.NewReader(`{"title":"hello","content":"hello world!"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if csrfHeader != "" {
+		req.Header.Set(middleware.CSRFHeader, csrfHeader)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestCSRFRequiresCookieSessions 只有开启 security.cookie_sessions 时才校验 CSRF 令牌
+func TestCSRFRequiresCookieSessions(t *testing.T) {
+	session := &http.Cookie{Name: "session", Value: "abc"}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		r := newTestRouter(t, newTestApp(t, "ratelimit.enabled=false"))
+		w := postWithCookies(r, "", session)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 from the JWT middleware, got %d %s", w.Code, w.Body)
+		}
+		if strings.Contains(w.Header().Get("Set-Cookie"), middleware.CSRFCookie) {
+			t.Errorf("unexpected CSRF cookie: %s", w.Header().Get("Set-Cookie"))
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		r := newTestRouter(t, newTestApp(t, "ratelimit.enabled=false", "security.cookie_sessions=true"))
+		w := postWithCookies(r, "", session)
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "csrf_token_invalid") {
+			t.Fatalf("missing token: expected 403 csrf_token_invalid, got %d %s", w.Code, w.Body)
+		}
+		if !strings.Contains(w.Header().Get("Set-Cookie"), middleware.CSRFCookie+"=") {
+			t.Errorf("expected a CSRF cookie to be issued, got %q", w.Header().Get("Set-Cookie"))
+		}
+
+		token := &http.Cookie{Name: middleware.CSRFCookie, Value: "t0ken"}
+		if w := postWithCookies(r, "wrong", session, token); w.Code != http.StatusForbidden {
+			t.Errorf("mismatched token: expected 403, got %d", w.Code)
+		}
+		if w := postWithCookies(r, "t0ken", session, token); w.Code != http.StatusUnauthorized {
+			t.Errorf("matching token: expected to reach the JWT middleware (401), got %d %s", w.Code, w.Body)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/service"
+	"blogSystem/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LogLevelRequest 调整日志级别请求
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error dpanic panic fatal"`
+}
+
+// LogLevelResponse 调整后的日志级别
+type LogLevelResponse struct {
+	Level    string `json:"level"`
+	Previous string `json:"previous"`
+}
+
+// UserResponse 管理接口中的用户信息，不含密码哈希
+type UserResponse struct {
+	ID         uint       `json:"id"`
+	Username   string     `json:"username"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+// UserListResponse 用户分页列表
+type UserListResponse struct {
+	Data []UserResponse `json:"data"`
+	PageInfo
+}
+
+// AdminHandler 运维管理接口，路由层负责限制为管理员访问
+type AdminHandler struct {
+	logLevel    zap.AtomicLevel
+	userService *service.UserService
+	pages       PageLimits
+}
+
+func NewAdminHandler(logLevel zap.AtomicLevel, userService *service.UserService, pages PageLimits) *AdminHandler {
+	return &AdminHandler{logLevel: logLevel, userService: userService, pages: pages}
+}
+
+// ListUsers 按注册时间倒序分页列出用户
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	req := h.pages.request(c)
+
+	page, err := h.userService.List(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := make([]UserResponse, 0, len(page.Items))
+	for i := range page.Items {
+		response = append(response, newUserResponse(&page.Items[i]))
+	}
+	c.JSON(http.StatusOK, UserListResponse{Data: response, PageInfo: newPageInfo(c, req, page)})
+}
+
+// SetLogLevel 运行时调整日志级别，立即对所有日志生效，重启后恢复为 LOG_LEVEL
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	previous := h.logLevel.String()
+	if err := h.logLevel.UnmarshalText([]byte(req.Level)); err != nil {
+		bindError(c, err)
+		return
+	}
+	logger.Ctx(c).Warn("Log level changed",
+		zap.String("to", req.Level),
+		zap.String("from", previous),
+	)
+
+	c.JSON(http.StatusOK, LogLevelResponse{Level: req.Level, Previous: previous})
+}
+
+func newUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Role:       user.Role,
+		CreatedAt:  user.CreatedAt,
+		DisabledAt: user.DisabledAt,
+	}
+}
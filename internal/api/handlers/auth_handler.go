@@ -10,6 +10,31 @@ import (
 	"go.uber.org/zap"
 )
 
+// RegisterRequest 注册请求，支持 JSON 和表单
+type RegisterRequest struct {
+	Username string `json:"username" form:"username" binding:"required,min=3,max=50"`
+	Password string `json:"password" form:"password" binding:"required,min=3"`
+	Email    string `json:"email" form:"email" binding:"required,email"`
+}
+
+// RegisterResponse 注册响应
+type RegisterResponse struct {
+	Message string `json:"message"`
+	UserID  uint   `json:"user_id"`
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse 登录响应，token 原样放入后续请求的 Authorization 头
+type LoginResponse struct {
+	Token   string `json:"token"`
+	Message string `json:"message"`
+}
+
 type AuthHandler struct {
 	authService *service.AuthService
 }
@@ -19,17 +44,13 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
-	logger.Info("ShouldBind START...", zap.Any("c.PostForm:", c.HandlerName()))
+	logger.Ctx(c.Request.Context()).Info("ShouldBind START...", zap.Any("c.PostForm:", c.HandlerName()))
 
-	var req struct {
-		Username string `json:"username" form:"username" binding:"required,min=3,max=50"`
-		Password string `json:"password" form:"password" binding:"required,min=3"`
-		Email    string `json:"email" form:"email" binding:"required,email"`
-	}
+	var req RegisterRequest
 
 	if err := c.ShouldBind(&req); err != nil {
-		logger.Info("ShouldBind START...", zap.Any("c.PostForm:", c.HandlerName()))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		logger.Ctx(c.Request.Context()).Info("ShouldBind START...", zap.Any("c.PostForm:", c.HandlerName()))
+		bindError(c, err)
 		return
 	}
 
@@ -39,36 +60,33 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Email:    req.Email,
 	}
 
-	if err := h.authService.Register(user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.authService.Register(c.Request.Context(), user); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "user registered successfully",
-		"user_id": user.ID,
+	c.JSON(http.StatusCreated, RegisterResponse{
+		Message: "user registered successfully",
+		UserID:  user.ID,
 	})
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
-	var req struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-	}
+	var req LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
-	token, err := h.authService.Login(req.Username, req.Password)
+	token, err := h.authService.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token":   token,
-		"message": "Login successful",
+	c.JSON(http.StatusOK, LoginResponse{
+		Token:   token,
+		Message: "Login successful",
 	})
 }
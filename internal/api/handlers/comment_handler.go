@@ -4,67 +4,129 @@ import (
 	"blogSystem/internal/domain"
 	"blogSystem/internal/service"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CreateCommentRequest 发表评论请求
+type CreateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=3"`
+}
+
+// CreateCommentResponse 发表评论响应
+type CreateCommentResponse struct {
+	ID      uint   `json:"id"`
+	Content string `json:"content"`
+	UserID  uint   `json:"user_id"`
+	PostID  uint   `json:"post_id"`
+}
+
+// CommentResponse 评论详情，只包含公开字段
+type CommentResponse struct {
+	ID        uint        `json:"id"`
+	Content   string      `json:"content"`
+	PostID    uint        `json:"post_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	User      UserSummary `json:"user"`
+	CanDelete bool        `json:"can_delete"`
+}
+
+// CommentListResponse 评论分页列表
+type CommentListResponse struct {
+	Data []CommentResponse `json:"data"`
+	PageInfo
+}
+
 type CommentHandler struct {
 	service *service.CommentService
+	pages   PageLimits
 }
 
-func NewCommentHandler(s *service.CommentService) *CommentHandler {
-	return &CommentHandler{service: s}
+func NewCommentHandler(s *service.CommentService, pages PageLimits) *CommentHandler {
+	return &CommentHandler{service: s, pages: pages}
 }
 
 func (h *CommentHandler) Create(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
-	postID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
-
-	var req struct {
-		Content string `json:"content" binding:"required,min=3"`
+	postID, err := parseID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
+
+	var req CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
 	comment := &domain.Comment{
 		Content: req.Content,
 		UserID:  userID,
-		PostID:  uint(postID),
+		PostID:  postID,
 	}
 
-	if err := h.service.Create(comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create comment"})
+	if err := h.service.Create(c.Request.Context(), comment); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":      comment.ID,
-		"content": comment.Content,
-		"user_id": comment.UserID,
-		"post_id": comment.PostID,
+	c.JSON(http.StatusCreated, CreateCommentResponse{
+		ID:      comment.ID,
+		Content: comment.Content,
+		UserID:  comment.UserID,
+		PostID:  comment.PostID,
 	})
 }
 
 func (h *CommentHandler) GetByPostID(c *gin.Context) {
-	postID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
-	comments, err := h.service.GetByPostID(uint(postID))
+	postID, err := parseID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, comments)
+	req := h.pages.request(c)
+	page, err := h.service.GetByPostID(c.Request.Context(), postID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 只返回公开字段，避免把关联用户的密码哈希等信息带出去
+	response := make([]CommentResponse, 0, len(page.Items))
+	for i := range page.Items {
+		response = append(response, newCommentResponse(c, &page.Items[i]))
+	}
+	// 与文章列表相同，不输出 Last-Modified
+	respondJSON(c, CommentListResponse{Data: response, PageInfo: newPageInfo(c, req, page)}, time.Time{})
 }
 
 func (h *CommentHandler) Delete(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
-	commentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	commentID, err := parseID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
 
-	if err := h.service.Delete(uint(commentID), userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.service.Delete(c.Request.Context(), commentID, userID); err != nil {
+		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "comment deleted"})
+	c.JSON(http.StatusOK, MessageResponse{Message: "comment deleted"})
+}
+
+func newCommentResponse(c *gin.Context, comment *domain.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		Content:   comment.Content,
+		PostID:    comment.PostID,
+		CreatedAt: comment.CreatedAt,
+		User: UserSummary{
+			ID:       comment.User.ID,
+			Username: comment.User.Username,
+		},
+		CanDelete: isOwner(c, comment.UserID),
+	}
 }
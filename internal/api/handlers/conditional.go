@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSON 输出 200 JSON 响应并支持条件请求，见 respondData
+func respondJSON(c *gin.Context, body any, lastModified time.Time) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	respondData(c, "application/json; charset=utf-8", data, lastModified)
+}
+
+// respondData 输出 200 响应并支持条件请求：ETag 取响应体的哈希，lastModified 非零时输出 Last-Modified（列表与 sitemap 传零值）；
+// 请求头 If-None-Match（优先）或 If-Modified-Since 表明客户端的副本仍然有效时返回 304，不输出响应体。
+// 响应体与当前用户有关（如 can_edit），不同用户得到的 ETag 不同
+func respondData(c *gin.Context, contentType string, body []byte, lastModified time.Time) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// notModified 按 RFC 9110 第 13.2.2 节的顺序判断：有 If-None-Match 时忽略 If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			// 弱比较：忽略 W/ 前缀
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
+
+// latest 返回一组时间中最新的一个，用作文章详情（文章与评论）的 Last-Modified
+func latest(times ...time.Time) time.Time {
+	var t time.Time
+	for _, candidate := range times {
+		if candidate.After(t) {
+			t = candidate
+		}
+	}
+	return t
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"blogSystem/internal/service"
+	"blogSystem/pkg/auth"
+	"blogSystem/pkg/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	registry    *health.Registry
+	userService *service.UserService
+}
+
+func NewHealthHandler(registry *health.Registry, userService *service.UserService) *HealthHandler {
+	return &HealthHandler{registry: registry, userService: userService}
+}
+
+// Healthz 存活检查：进程能处理请求即返回 200，不检查外部依赖
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusOK})
+}
+
+// Readyz 就绪检查：任一检查失败或服务正在关闭时返回 503。
+// 带 verbose 参数时返回每项检查的详情（含错误信息），仅管理员可用
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	_, verbose := c.GetQuery("verbose")
+	if verbose {
+		userID, ok := currentUserID(c)
+		if !ok {
+			_ = c.Error(auth.ErrMissingToken)
+			return
+		}
+		if err := h.userService.RequireAdmin(c.Request.Context(), userID); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	report := h.registry.Check(c.Request.Context())
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	if !verbose {
+		report.Checks = nil
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
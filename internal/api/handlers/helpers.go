@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"blogSystem/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID 返回当前登录用户 ID；匿名访客返回 false
+func currentUserID(c *gin.Context) (uint, bool) {
+	v, ok := c.Get("userID")
+	if !ok {
+		return 0, false
+	}
+	userID, ok := v.(uint)
+	return userID, ok
+}
+
+// isOwner 判断当前访问者是否为资源所有者
+func isOwner(c *gin.Context, ownerID uint) bool {
+	userID, ok := currentUserID(c)
+	return ok && userID == ownerID
+}
+
+// bindError 记录请求绑定/校验错误，由错误处理中间件统一输出字段级详情
+func bindError(c *gin.Context, err error) {
+	_ = c.Error(err).SetType(gin.ErrorTypeBind)
+}
+
+// parseID 解析路径参数中的资源 ID
+func parseID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, service.NewValidationError("invalid_id", "invalid "+name)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// legacyTimeFormat 旧版响应的时间格式：UTC，精确到毫秒
+const legacyTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// LegacyPostSummary 旧版文章列表中的文章，不含 can_edit 等查看者相关字段
+type LegacyPostSummary struct {
+	ID        uint        `json:"id"`
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	UserID    uint        `json:"user_id"`
+	CreatedAt string      `json:"created_at"`
+	Author    UserSummary `json:"author"`
+}
+
+// LegacyPostResponse 旧版文章详情，comments 没有评论时为空数组
+type LegacyPostResponse struct {
+	LegacyPostSummary
+	Comments []LegacyPostComment `json:"comments"`
+}
+
+// LegacyPostComment 旧版文章详情中的评论
+type LegacyPostComment struct {
+	ID        uint        `json:"id"`
+	Content   string      `json:"content"`
+	CreatedAt string      `json:"created_at"`
+	User      UserSummary `json:"user"`
+}
+
+// LegacyPostList 旧版文章列表：按页码翻页，没有 v1 的 next/prev 游标链接
+type LegacyPostList struct {
+	Data  []LegacyPostSummary `json:"data"`
+	Page  int                 `json:"page"`
+	Size  int                 `json:"size"`
+	Total int64               `json:"total"`
+}
+
+// LegacyComment 旧版评论列表中的评论，键名与改造前直接序列化 domain.Comment 时相同。
+// 为兼容保留 User.Password、User.Email 等键，但只填公开字段：密码与邮箱始终为空字符串，
+// Post 只有 ID（改造前没有预加载文章，该对象本来就是零值）
+type LegacyComment struct {
+	ID        uint
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt *string
+	Content   string
+	UserID    uint
+	PostID    uint
+	User      LegacyUser
+	Post      LegacyPost
+}
+
+// LegacyUser 旧版评论中的评论者
+type LegacyUser struct {
+	ID        uint
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt *string
+	Username  string
+	Password  string
+	Email     string
+	Posts     []any
+}
+
+// LegacyPost 旧版评论中的所属文章
+type LegacyPost struct {
+	ID        uint
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt *string
+	Title     string
+	Content   string
+	UserID    uint
+	User      LegacyUser
+	Comments  []any
+}
+
+// LegacyHandler 旧版路由中响应结构与 v1 不同的接口，按改造前的格式输出；
+// 其余旧版路由的请求与响应与 v1 相同，直接复用 v1 的处理器
+type LegacyHandler struct {
+	posts    *service.PostService
+	comments *service.CommentService
+	pages    PageLimits
+}
+
+func NewLegacyHandler(posts *service.PostService, comments *service.CommentService, pages PageLimits) *LegacyHandler {
+	return &LegacyHandler{posts: posts, comments: comments, pages: pages}
+}
+
+// ListPosts 文章列表（/listPosts），只支持 page/size 参数，游标分页仅在 /api/v1 提供
+func (h *LegacyHandler) ListPosts(c *gin.Context) {
+	req := h.pages.request(c)
+	req.Cursor, req.WithTotal = "", true
+
+	page, err := h.posts.List(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := LegacyPostList{Data: make([]LegacyPostSummary, 0, len(page.Items)), Page: req.Page, Size: req.Size, Total: *page.Total}
+	for i := range page.Items {
+		response.Data = append(response.Data, newLegacyPostSummary(&page.Items[i]))
+	}
+	respondJSON(c, response, time.Time{})
+}
+
+// Comments 文章的全部评论（/getCommentById/:id），与改造前一样不分页，直接返回数组
+func (h *LegacyHandler) Comments(c *gin.Context) {
+	postID, err := parseID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := make([]LegacyComment, 0)
+	req := service.PageRequest{Page: 1, Size: h.pages.MaxSize}
+	for {
+		page, err := h.comments.GetByPostID(c.Request.Context(), postID, req)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		for i := range page.Items {
+			response = append(response, newLegacyComment(&page.Items[i]))
+		}
+		if page.Next == "" {
+			break
+		}
+		req = service.PageRequest{Cursor: page.Next, Size: h.pages.MaxSize}
+	}
+	respondJSON(c, response, time.Time{})
+}
+
+// GetPost 文章详情（/getPostById/:id）
+func (h *LegacyHandler) GetPost(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	post, err := h.posts.GetByID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := LegacyPostResponse{
+		LegacyPostSummary: newLegacyPostSummary(post),
+		Comments:          make([]LegacyPostComment, 0, len(post.Comments)),
+	}
+	lastModified := post.UpdatedAt
+	for i := range post.Comments {
+		comment := &post.Comments[i]
+		response.Comments = append(response.Comments, LegacyPostComment{
+			ID:        comment.ID,
+			Content:   comment.Content,
+			CreatedAt: legacyTime(comment.CreatedAt),
+			User:      UserSummary{ID: comment.User.ID, Username: comment.User.Username},
+		})
+		lastModified = latest(lastModified, comment.UpdatedAt)
+	}
+	respondJSON(c, response, lastModified)
+}
+
+func newLegacyPostSummary(post *domain.Post) LegacyPostSummary {
+	return LegacyPostSummary{
+		ID:        post.ID,
+		Title:     post.Title,
+		Content:   post.Content,
+		UserID:    post.UserID,
+		CreatedAt: legacyTime(post.CreatedAt),
+		Author:    UserSummary{ID: post.User.ID, Username: post.User.Username},
+	}
+}
+
+func newLegacyComment(comment *domain.Comment) LegacyComment {
+	return LegacyComment{
+		ID:        comment.ID,
+		CreatedAt: legacyTime(comment.CreatedAt),
+		UpdatedAt: legacyTime(comment.UpdatedAt),
+		Content:   comment.Content,
+		UserID:    comment.UserID,
+		PostID:    comment.PostID,
+		User: LegacyUser{
+			ID:        comment.User.ID,
+			CreatedAt: legacyTime(comment.User.CreatedAt),
+			UpdatedAt: legacyTime(comment.User.UpdatedAt),
+			Username:  comment.User.Username,
+		},
+		Post: LegacyPost{ID: comment.PostID},
+	}
+}
+
+func legacyTime(t time.Time) string {
+	return t.UTC().Format(legacyTimeFormat)
+}
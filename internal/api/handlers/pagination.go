@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"blogSystem/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageLimits 分页参数的默认每页条数与上限
+type PageLimits struct {
+	DefaultSize int
+	MaxSize     int
+}
+
+// request 解析分页参数：cursor 为响应中 next/prev 链接携带的游标，page 为兼容旧客户端的页码，
+// total=false 跳过总条数统计；非法的页码与条数回退为默认值，非法的游标由服务层报错
+func (l PageLimits) request(c *gin.Context) service.PageRequest {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(l.DefaultSize)))
+	withTotal, err := strconv.ParseBool(c.DefaultQuery("total", "true"))
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > l.MaxSize {
+		size = l.DefaultSize
+	}
+	if err != nil {
+		withTotal = true
+	}
+	return service.PageRequest{Cursor: c.Query("cursor"), Page: page, Size: size, WithTotal: withTotal}
+}
+
+// PageInfo 列表响应共有的分页信息，与 data 平铺在同一层
+type PageInfo struct {
+	Page  int    `json:"page,omitempty"`  // 按页码翻页时回显页码，使用游标时省略
+	Size  int    `json:"size"`            // 每页条数
+	Total *int64 `json:"total,omitempty"` // 满足条件的总条数，请求带 total=false 时省略
+	Next  string `json:"next,omitempty"`  // 下一页的链接，没有下一页时省略
+	Prev  string `json:"prev,omitempty"`  // 上一页的链接，没有上一页时省略
+}
+
+// newPageInfo 由查询结果生成分页信息，并把相邻页的链接同时写入 Link 响应头（RFC 8288）
+func newPageInfo[T any](c *gin.Context, req service.PageRequest, page *service.Page[T]) PageInfo {
+	info := PageInfo{Size: req.Size, Total: page.Total}
+	if req.Cursor == "" {
+		info.Page = req.Page
+	}
+	if page.Next != "" {
+		info.Next = pageLink(c, page.Next)
+		c.Writer.Header().Add("Link", "<"+info.Next+`>; rel="next"`)
+	}
+	if page.Prev != "" {
+		info.Prev = pageLink(c, page.Prev)
+		c.Writer.Header().Add("Link", "<"+info.Prev+`>; rel="prev"`)
+	}
+	return info
+}
+
+// pageLink 相邻页的相对链接：保留当前请求的其余参数，以游标替换页码
+func pageLink(c *gin.Context, cursor string) string {
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + query.Encode()
+}
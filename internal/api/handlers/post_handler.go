@@ -3,33 +3,77 @@ package handlers
 import (
 	"blogSystem/internal/domain"
 	"blogSystem/internal/service"
-	"blogSystem/pkg/logger"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
+// CreatePostRequest 创建文章请求
+type CreatePostRequest struct {
+	Title   string `json:"title" binding:"required,min=3,max=200"`
+	Content string `json:"content" binding:"required,min=10"`
+}
+
+// UpdatePostRequest 更新文章请求，未提供的字段保持不变
+type UpdatePostRequest struct {
+	Title   string `json:"title" binding:"omitempty,min=3,max=200"`
+	Content string `json:"content" binding:"omitempty,min=10"`
+}
+
+// CreatePostResponse 创建文章响应
+type CreatePostResponse struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	UserID  uint   `json:"user_id"`
+}
+
+// UserSummary 文章作者、评论者等关联用户的公开信息
+type UserSummary struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// PostResponse 文章详情；列表中不包含 comments
+type PostResponse struct {
+	ID        uint              `json:"id"`
+	Title     string            `json:"title"`
+	Content   string            `json:"content"`
+	UserID    uint              `json:"user_id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Author    UserSummary       `json:"author"`
+	CanEdit   bool              `json:"can_edit"`
+	Comments  []CommentResponse `json:"comments,omitempty"`
+}
+
+// PostListResponse 文章分页列表
+type PostListResponse struct {
+	Data []PostResponse `json:"data"`
+	PageInfo
+}
+
+// MessageResponse 仅包含提示信息的响应
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
 type PostHandler struct {
 	postService *service.PostService
+	pages       PageLimits
 }
 
-func NewPostHandler(postService1 *service.PostService) *PostHandler {
-	return &PostHandler{postService: postService1}
+func NewPostHandler(postService1 *service.PostService, pages PageLimits) *PostHandler {
+	return &PostHandler{postService: postService1, pages: pages}
 }
 
 // 创建文章
 func (h *PostHandler) Create(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
-	var req struct {
-		Title   string `json:"title" binding:"required,min=3,max=200"`
-		Content string `json:"content" binding:"required,min=10"`
-	}
-
+	var req CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
@@ -39,83 +83,57 @@ func (h *PostHandler) Create(c *gin.Context) {
 		UserID:  userID,
 	}
 
-	if err := h.postService.Create(post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create post"})
+	if err := h.postService.Create(c.Request.Context(), post); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":      post.ID,
-		"title":   post.Title,
-		"content": post.Content,
-		"user_id": post.UserID,
+	c.JSON(http.StatusCreated, CreatePostResponse{
+		ID:      post.ID,
+		Title:   post.Title,
+		Content: post.Content,
+		UserID:  post.UserID,
 	})
 }
 
 // GetById 获取文章详情
 func (h *PostHandler) GetById(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	logger.Info("GetById START", zap.Error(err))
+	id, err := parseID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		_ = c.Error(err)
 		return
 	}
 
-	post, err := h.postService.GetByID(uint(id))
+	post, err := h.postService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	// 构建响应数据
-	response := gin.H{
-		"id":         post.ID,
-		"title":      post.Title,
-		"content":    post.Content,
-		"user_id":    post.UserID,
-		"created_at": post.CreatedAt,
-		"author": gin.H{
-			"id":       post.User.ID,
-			"username": post.User.Username,
-		},
-	}
-	// 添加评论数据
-	if len(post.Comments) > 0 {
-		var comments []gin.H
-		for _, comment := range post.Comments {
-			comments = append(comments, gin.H{
-				"id":         comment.ID,
-				"content":    comment.Content,
-				"created_at": comment.CreatedAt,
-				"user": gin.H{
-					"id":       comment.User.ID,
-					"username": comment.User.Username,
-				},
-			})
-		}
-		response["comments"] = comments
+	// 构建响应数据，附带评论；Last-Modified 取文章与评论中最新的更新时间
+	response := newPostResponse(c, post)
+	lastModified := post.UpdatedAt
+	for i := range post.Comments {
+		response.Comments = append(response.Comments, newCommentResponse(c, &post.Comments[i]))
+		lastModified = latest(lastModified, post.Comments[i].UpdatedAt)
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, response, lastModified)
 }
 
 // Update 更新文章
 func (h *PostHandler) Update(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, err := parseID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		_ = c.Error(err)
 		return
 	}
 
-	var req struct {
-		Title   string `json:"title" binding:"omitempty,min=3,max=200"`
-		Content string `json:"content" binding:"omitempty,min=10"`
-	}
-
+	var req UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
@@ -127,69 +145,100 @@ func (h *PostHandler) Update(c *gin.Context) {
 		updates["content"] = req.Content
 	}
 
-	if err := h.postService.Update(uint(id), userID, updates); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "update failed: " + err.Error()})
+	if err := h.postService.Update(c.Request.Context(), id, userID, updates); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "post updated successfully"})
+	c.JSON(http.StatusOK, MessageResponse{Message: "post updated successfully"})
 }
 
 // Delete 删除文章
 func (h *PostHandler) Delete(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, err := parseID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		_ = c.Error(err)
 		return
 	}
 
-	if err := h.postService.Delete(uint(id), userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "delete failed: " + err.Error()})
+	if err := h.postService.Delete(c.Request.Context(), id, userID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "post deleted successfully"})
+	c.JSON(http.StatusOK, MessageResponse{Message: "post deleted successfully"})
 }
 
 // List 获取文章列表
 func (h *PostHandler) List(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+	req := h.pages.request(c)
+
+	page, err := h.postService.List(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// 列表只用 ETag：删除文章或文章移出当前页都不会产生更新的时间戳，Last-Modified 会停留在旧值
+	respondJSON(c, newPostListResponse(c, req, page), time.Time{})
+}
 
-	if page < 1 {
-		page = 1
+// ListByAuthor 某个用户发表的文章列表（作者页面）
+func (h *PostHandler) ListByAuthor(c *gin.Context) {
+	userID, err := parseID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
-	if size < 1 || size > 100 {
-		size = 10
+	req := h.pages.request(c)
+
+	page, err := h.postService.ListByAuthor(c.Request.Context(), userID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
 
-	posts, err := h.postService.List(page, size)
+	respondJSON(c, newPostListResponse(c, req, page), time.Time{})
+}
+
+// Search 按关键词检索文章，参数 q 为关键词
+func (h *PostHandler) Search(c *gin.Context) {
+	req := h.pages.request(c)
+
+	page, err := h.postService.Search(c.Request.Context(), c.Query("q"), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get posts"})
+		_ = c.Error(err)
 		return
 	}
 
-	var response []gin.H
-	for _, post := range posts {
-		response = append(response, gin.H{
-			"id":         post.ID,
-			"title":      post.Title,
-			"content":    post.Content,
-			"user_id":    post.UserID,
-			"created_at": post.CreatedAt,
-			"author": gin.H{
-				"id":       post.User.ID,
-				"username": post.User.Username,
-			},
-		})
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"data":  response,
-		"page":  page,
-		"size":  size,
-		"total": len(response),
-	})
+	respondJSON(c, newPostListResponse(c, req, page), time.Time{})
+}
+
+func newPostListResponse(c *gin.Context, req service.PageRequest, page *service.Page[domain.Post]) PostListResponse {
+	response := make([]PostResponse, 0, len(page.Items))
+	for i := range page.Items {
+		response = append(response, newPostResponse(c, &page.Items[i]))
+	}
+
+	return PostListResponse{
+		Data:     response,
+		PageInfo: newPageInfo(c, req, page),
+	}
+}
+
+func newPostResponse(c *gin.Context, post *domain.Post) PostResponse {
+	return PostResponse{
+		ID:        post.ID,
+		Title:     post.Title,
+		Content:   post.Content,
+		UserID:    post.UserID,
+		CreatedAt: post.CreatedAt,
+		Author: UserSummary{
+			ID:       post.User.ID,
+			Username: post.User.Username,
+		},
+		CanEdit: isOwner(c, post.UserID),
+	}
 }
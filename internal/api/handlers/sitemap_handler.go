@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"blogSystem/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+type SitemapHandler struct {
+	service        *service.SitemapService
+	robotsDisallow []string
+}
+
+func NewSitemapHandler(s *service.SitemapService, robotsDisallow []string) *SitemapHandler {
+	return &SitemapHandler{service: s, robotsDisallow: robotsDisallow}
+}
+
+// Sitemap 输出 /sitemap.xml（超过 50000 条时为 sitemap 索引）
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	body, err := h.service.Index(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	respondData(c, xmlContentType, body, time.Time{})
+}
+
+// SitemapPage 输出分页 sitemap，路径形如 /sitemaps/2.xml
+func (h *SitemapHandler) SitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil {
+		_ = c.Error(service.ErrSitemapPageNotFound)
+		return
+	}
+
+	body, err := h.service.Page(c.Request.Context(), page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	respondData(c, xmlContentType, body, time.Time{})
+}
+
+// Robots 输出 /robots.txt，禁止抓取的路径由 ROBOTS_DISALLOW 配置
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(h.robotsDisallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range h.robotsDisallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + h.service.BaseURL() + "/sitemap.xml\n")
+
+	c.String(http.StatusOK, b.String())
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func varyOf(w *httptest.ResponseRecorder) []string {
+	var vary []string
+	for _, value := range w.Header().Values("Vary") {
+		for _, header := range strings.Split(value, ",") {
+			vary = append(vary, strings.TrimSpace(header))
+		}
+	}
+	return vary
+}
+
+// TestListsRevalidateWithETagOnly 列表响应不带 Last-Modified，删除文章后条件请求不会错误地返回 304
+func TestListsRevalidateWithETagOnly(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false", "cache.enabled=false")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+
+	if w := do(r, http.MethodPost, "/api/v1/auth/register", "", `{"username":"alice","password":"secret","email":"alice@example.com"}`); w.Code != http.StatusCreated {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+	token := login(t, r, "alice", "secret")
+	for _, title := range []string{"first", "second"} {
+		w := do(r, http.MethodPost, "/api/v1/posts", token, `{"title":"`+title+`","content":"hello world!"}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create post: %d %s", w.Code, w.Body)
+		}
+	}
+	if w := do(r, http.MethodPost, "/api/v1/posts/1/comments", token, `{"content":"nice post"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create comment: %d %s", w.Code, w.Body)
+	}
+
+	for _, path := range []string{"/api/v1/posts", "/api/v1/posts/search?q=hello", "/api/v1/users/1/posts", "/api/v1/posts/1/comments"} {
+		w := do(r, http.MethodGet, path, "", "")
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == "" {
+			t.Fatalf("GET %s: expected 200 with an ETag, got %d %v", path, w.Code, w.Header())
+		}
+		if lm := w.Header().Get("Last-Modified"); lm != "" {
+			t.Errorf("GET %s: unexpected Last-Modified %q", path, lm)
+		}
+	}
+	if w := do(r, http.MethodGet, "/api/v1/posts/1", "", ""); w.Header().Get("Last-Modified") == "" {
+		t.Errorf("post detail: expected Last-Modified")
+	}
+
+	// 删除最新的文章后，列表中剩余文章的更新时间都早于客户端副本，只有 ETag 能反映变化
+	etag := do(r, http.MethodGet, "/api/v1/posts", "", "").Header().Get("ETag")
+	if w := do(r, http.MethodDelete, "/api/v1/posts/2", token, ""); w.Code != http.StatusOK {
+		t.Fatalf("delete post: %d %s", w.Code, w.Body)
+	}
+	for header, value := range map[string]string{
+		"If-None-Match":     etag,
+		"If-Modified-Since": "Fri, 01 Jan 2100 00:00:00 GMT",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s after deleting a post: expected 200, got %d", header, w.Code)
+		}
+	}
+}
+
+// TestVaryMatchesWhatTheResponseDependsOn 文章与评论按携带凭据的请求头区分，sitemap 不区分，
+// 只有本地化的错误响应带 Vary: Accept-Language
+func TestVaryMatchesWhatTheResponseDependsOn(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides []string
+		path      string
+		want      []string
+	}{
+		{"post list", nil, "/api/v1/posts", []string{"Authorization"}},
+		{"post list with cookie sessions", []string{"security.cookie_sessions=true"}, "/api/v1/posts", []string{"Authorization", "Cookie"}},
+		{"sitemap", nil, "/robots.txt", nil},
+		{"localized error", nil, "/api/v1/posts/999", []string{"Authorization", "Accept-Language"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newTestApp(t, append([]string{"ratelimit.enabled=false", "cache.enabled=false"}, tc.overrides...)...)
+			migrateTestDB(t, a)
+			r := newTestRouter(t, a)
+
+			w := do(r, http.MethodGet, tc.path, "", "")
+			if got := varyOf(w); !slices.Equal(got, tc.want) {
+				t.Errorf("Vary %v, want %v", got, tc.want)
+			}
+			wantLanguage := ""
+			if slices.Contains(tc.want, "Accept-Language") {
+				wantLanguage = "zh-CN"
+			}
+			if got := w.Header().Get("Content-Language"); got != wantLanguage {
+				t.Errorf("Content-Language %q, want %q", got, wantLanguage)
+			}
+		})
+	}
+}
+
+// TestSitemapConditionalRequest 本项目没有 feed 接口，sitemap 是面向爬虫的列表，同样支持条件请求
+func TestSitemapConditionalRequest(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+
+	w := do(r, http.MethodGet, "/sitemap.xml", "", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Header().Get("Cache-Control") != "public, max-age=3600" {
+		t.Fatalf("expected 200 with an ETag and a public Cache-Control, got %d %v", w.Code, w.Header())
+	}
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected an empty 304, got %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestLegacyRoutesKeepErrorFormat 旧版路由的错误响应为 {"error": "..."}，v1 路由为 problem+json
+func TestLegacyRoutesKeepErrorFormat(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+	if w := do(r, http.MethodPost, "/api/v1/auth/register", "", `{"username":"alice","password":"secret","email":"alice@example.com"}`); w.Code != http.StatusCreated {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+
+	cases := []struct {
+		name, method, path, body string
+		status                   int
+	}{
+		{"validation", http.MethodPost, "/register", "", http.StatusBadRequest},
+		{"authentication", http.MethodPost, "/createPost", "", http.StatusUnauthorized},
+		{"not found", http.MethodGet, "/getPostById/42", "", http.StatusNotFound},
+		// 改造前没有 409，重复注册返回 400
+		{"username taken", http.MethodPost, "/register", `{"username":"alice","password":"secret","email":"alice@example.com"}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := do(r, tc.method, tc.path, "", tc.body)
+			if w.Code != tc.status {
+				t.Fatalf("expected %d, got %d %s", tc.status, w.Code, w.Body)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type %q, want application/json", ct)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if msg, ok := body["error"].(string); !ok || msg == "" || len(body) != 1 {
+				t.Errorf("expected a single non-empty error field, got %s", w.Body)
+			}
+		})
+	}
+
+	w := do(r, http.MethodPost, "/api/v1/auth/register", "", "")
+	if ct := w.Header().Get("Content-Type"); w.Code != http.StatusBadRequest || ct != "application/problem+json" {
+		t.Errorf("v1: expected 400 application/problem+json, got %d %q", w.Code, ct)
+	}
+}
+
+// keysOf 返回 JSON 对象的键，按字母排序
+func keysOf(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seedLegacyData 注册 alice 并发表一篇文章和一条评论，返回 alice 的令牌
+func seedLegacyData(t *testing.T, r *gin.Engine) string {
+	t.Helper()
+	if w := do(r, http.MethodPost, "/api/v1/auth/register", "", `{"username":"alice","password":"secret","email":"alice@example.com"}`); w.Code != http.StatusCreated {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+	token := login(t, r, "alice", "secret")
+	if w := do(r, http.MethodPost, "/api/v1/posts", token, `{"title":"hello","content":"hello world!"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create post: %d %s", w.Code, w.Body)
+	}
+	if w := do(r, http.MethodPost, "/api/v1/posts/1/comments", token, `{"content":"nice post"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create comment: %d %s", w.Code, w.Body)
+	}
+	return token
+}
+
+// TestLegacyPostDetailHasNoViewerFlags can_edit、can_delete 等查看者相关字段只出现在 v1 响应中
+func TestLegacyPostDetailHasNoViewerFlags(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false", "cache.enabled=false")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+	token := seedLegacyData(t, r)
+
+	var legacy struct {
+		Post     map[string]any
+		Comments []map[string]any
+	}
+	w := do(r, http.MethodGet, "/getPostById/1", token, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("legacy detail: %d %s", w.Code, w.Body)
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &legacy.Post); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := keysOf(legacy.Post), []string{"author", "comments", "content", "created_at", "id", "title", "user_id"}; !slices.Equal(got, want) {
+		t.Errorf("legacy post keys %v, want %v", got, want)
+	}
+	raw, _ := json.Marshal(legacy.Post["comments"])
+	if err := json.Unmarshal(raw, &legacy.Comments); err != nil || len(legacy.Comments) != 1 {
+		t.Fatalf("legacy comments: %s", raw)
+	}
+	if got, want := keysOf(legacy.Comments[0]), []string{"content", "created_at", "id", "user"}; !slices.Equal(got, want) {
+		t.Errorf("legacy comment keys %v, want %v", got, want)
+	}
+
+	var v1 map[string]any
+	if err := json.Unmarshal(do(r, http.MethodGet, "/api/v1/posts/1", token, "").Body.Bytes(), &v1); err != nil {
+		t.Fatal(err)
+	}
+	if v1["can_edit"] != true {
+		t.Errorf("v1 detail: expected can_edit=true for the author, got %v", v1["can_edit"])
+	}
+}
+
+// TestLegacyListsKeepBaselineShapes /listPosts 按页码分页且没有 next/prev，/getCommentById 返回评论数组
+func TestLegacyListsKeepBaselineShapes(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false", "cache.enabled=false")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+	token := seedLegacyData(t, r)
+	if w := do(r, http.MethodPost, "/api/v1/posts", token, `{"title":"second","content":"hello again!"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create post: %d %s", w.Code, w.Body)
+	}
+
+	w := do(r, http.MethodGet, "/listPosts?page=2&size=1", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("listPosts: %d %s", w.Code, w.Body)
+	}
+	if link := w.Header().Get("Link"); strings.Contains(link, `rel="next"`) || strings.Contains(link, `rel="prev"`) {
+		t.Errorf("unexpected pagination Link header %q", link)
+	}
+	var list struct {
+		Data  []map[string]any
+		Page  int
+		Size  int
+		Total int
+	}
+	var top map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &top); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := keysOf(top), []string{"data", "page", "size", "total"}; !slices.Equal(got, want) {
+		t.Errorf("listPosts keys %v, want %v", got, want)
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if list.Page != 2 || list.Size != 1 || list.Total != 2 || len(list.Data) != 1 {
+		t.Fatalf("listPosts page 2: %s", w.Body)
+	}
+	if got, want := keysOf(list.Data[0]), []string{"author", "content", "created_at", "id", "title", "user_id"}; !slices.Equal(got, want) {
+		t.Errorf("listPosts post keys %v, want %v", got, want)
+	}
+	if list.Data[0]["title"] != "hello" {
+		t.Errorf("page 2 should hold the older post, got %v", list.Data[0]["title"])
+	}
+
+	w = do(r, http.MethodGet, "/getCommentById/1", "", "")
+	var comments []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &comments); err != nil || len(comments) != 1 {
+		t.Fatalf("getCommentById: expected an array with one comment, got %d %s", w.Code, w.Body)
+	}
+	if got, want := keysOf(comments[0]), []string{"Content", "CreatedAt", "DeletedAt", "ID", "Post", "PostID", "UpdatedAt", "User", "UserID"}; !slices.Equal(got, want) {
+		t.Errorf("comment keys %v, want %v", got, want)
+	}
+	user := comments[0]["User"].(map[string]any)
+	if user["Username"] != "alice" || user["Password"] != "" || user["Email"] != "" {
+		t.Errorf("comment user should only carry public fields, got %v", user)
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrape 以本机地址请求 /metrics，返回指标文本
+func scrape(t *testing.T, r http.Handler) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "127.0.0.1:40000"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: %d %s", w.Code, w.Body)
+	}
+	return w.Body.String()
+}
+
+// TestMetricsArePerApp 每个应用实例有自己的注册表：创建多个实例不会重复注册，指标互不混杂
+func TestMetricsArePerApp(t *testing.T) {
+	first, second := newTestApp(t), newTestApp(t)
+	r1, r2 := newTestRouter(t, first), newTestRouter(t, second)
+
+	w := httptest.NewRecorder()
+	r1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	const served = `blog_http_requests_total{method="GET",route="/healthz",status="200"} 1`
+	if body := scrape(t, r1); !strings.Contains(body, served) {
+		t.Fatalf("first app is missing %s", served)
+	}
+	body := scrape(t, r2)
+	if strings.Contains(body, `route="/healthz"`) {
+		t.Fatal("second app reports requests served by the first")
+	}
+	if !strings.Contains(body, `go_sql_max_open_connections{db_name="blog"}`) {
+		t.Fatal("second app is missing its connection pool metrics")
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"blogSystem/pkg/logger"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AccessLog 每个请求输出一行 JSON 访问日志，需放在 RequestID 之后；5xx 响应记为 error 级别
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		body := &countingReader{ReadCloser: c.Request.Body}
+		if c.Request.Body != nil {
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int64("bytes_in", body.n),
+			zap.Int("bytes_out", max(c.Writer.Size(), 0)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if userID, ok := c.Get("userID"); ok {
+			fields = append(fields, zap.Any("user_id", userID))
+		}
+
+		level := zapcore.InfoLevel
+		if status >= http.StatusInternalServerError {
+			level = zapcore.ErrorLevel
+		}
+		logger.Ctx(c.Request.Context()).Log(level, logger.AccessLogMessage, fields...)
+	}
+}
+
+// Recovery 捕获处理过程中的 panic，记录堆栈并返回 500，代替 gin 默认的纯文本 Recovery
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Ctx(c.Request.Context()).Error("Panic recovered",
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				if !c.Writer.Written() {
+					AbortWithProblem(c, LocalizedProblem(c, http.StatusInternalServerError, "internal_error", "internal server error"))
+				} else {
+					c.Abort()
+				}
+			}
+		}()
+		c.Next()
+	}
+}
+
+// countingReader 统计实际读取的请求体字节数（Content-Length 可能缺失）
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"blogSystem/internal/service"
+	"blogSystem/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin 仅允许管理员访问，需放在 TokenIssuer.JWTMiddleware 之后；
+// 每次请求都查询用户角色，降级或禁用立即生效
+func RequireAdmin(userService *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		if !ok {
+			_ = c.Error(auth.ErrMissingToken)
+			c.Abort()
+			return
+		}
+		if err := userService.RequireAdmin(c.Request.Context(), userID.(uint)); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoStore 默认的缓存策略：响应不得被任何缓存保存，写操作、认证、管理接口与错误响应都使用它；
+// 可以缓存的路由再通过 CacheControl 覆盖
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Next()
+	}
+}
+
+// CacheControl 可缓存路由的策略，需放在（可选）JWT 中间件之后：
+//
+//	匿名请求：public, max-age=<maxAge>，CDN 与浏览器都可以缓存，maxAge 为 0 时每次使用前都要重新验证；
+//	已登录请求：private, no-cache，响应含 can_edit 等个人数据，只允许浏览器缓存并在使用前用 ETag 重新验证。
+//
+// vary 为响应体所依赖的请求头，如随登录用户变化的路由传 Authorization（启用 Cookie 会话时再加 Cookie），
+// 携带凭据的请求不会命中 CDN 上为匿名访客缓存的副本；与请求无关的响应（如 sitemap）不传
+func CacheControl(maxAge time.Duration, vary ...string) gin.HandlerFunc {
+	public := "public, no-cache"
+	if maxAge > 0 {
+		public = "public, max-age=" + strconv.FormatInt(int64(maxAge.Seconds()), 10)
+	}
+
+	return func(c *gin.Context) {
+		for _, header := range vary {
+			c.Writer.Header().Add("Vary", header)
+		}
+		if _, ok := c.Get("userID"); ok {
+			c.Header("Cache-Control", "private, no-cache")
+		} else {
+			c.Header("Cache-Control", public)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated 为旧版路由添加弃用响应头：
+//
+//	Deprecation: @<unix 时间戳>（RFC 9745）
+//	Sunset: <HTTP 日期>（RFC 8594），在此之后路由可能被移除
+//	Link: <successor>; rel="successor-version"
+//
+// successor 中的 :param 占位符会被替换为当前请求的路径参数，如 /api/v1/posts/:id
+func Deprecated(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetValue)
+		if successor != "" {
+			link := successor
+			for _, p := range c.Params {
+				link = strings.ReplaceAll(link, ":"+p.Key, p.Value)
+			}
+			c.Header("Link", "<"+link+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"blogSystem/internal/service"
+	"blogSystem/pkg/auth"
+	"blogSystem/pkg/i18n"
+	"blogSystem/pkg/logger"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	legacyKey          = "legacyErrors"
+)
+
+// Problem RFC 7807 错误响应体
+//
+//	type 指向错误说明文档，code 是稳定的机器可读错误码，
+//	errors 仅在参数校验失败时出现，逐个字段说明原因。
+type Problem struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Code     string           `json:"code"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+}
+
+// LegacyError 旧版路由的错误响应体，与改造前的 {"error": "..."} 保持一致
+type LegacyError struct {
+	Error string `json:"error"`
+}
+
+// FieldViolation 单个字段的校验失败信息
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+var kindStatus = map[service.ErrorKind]int{
+	service.KindValidation:   http.StatusBadRequest,
+	service.KindUnauthorized: http.StatusUnauthorized,
+	service.KindForbidden:    http.StatusForbidden,
+	service.KindNotFound:     http.StatusNotFound,
+	service.KindConflict:     http.StatusConflict,
+}
+
+var registerValidatorOnce sync.Once
+
+// ErrorHandler 统一错误处理中间件
+//
+//	处理器只需 c.Error(err) 后返回，由这里把错误转换为 problem+json 响应：
+//	service.Error 按分类映射状态码，绑定/校验错误返回字段级详情，
+//	其余未知错误记录日志后统一返回 500，不向客户端暴露内部信息。
+//	detail 与字段错误信息按 Locale 中间件协商出的语言输出。
+func ErrorHandler(log *zap.Logger) gin.HandlerFunc {
+	registerValidatorOnce.Do(func() { setupValidator(log) })
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		last := c.Errors.Last()
+		err := last.Err
+		if c.Writer.Written() {
+			logger.Ctx(c.Request.Context()).Error("Error after response was written",
+				zap.String("path", c.Request.URL.Path),
+				zap.Error(err),
+			)
+			return
+		}
+
+		problem := toProblem(err, last.IsType(gin.ErrorTypeBind), LocaleOf(c))
+		if problem.Status == http.StatusInternalServerError {
+			trace.SpanFromContext(c.Request.Context()).RecordError(err)
+			logger.Ctx(c.Request.Context()).Error("Unhandled request error",
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Error(err),
+			)
+		}
+		problem.Instance = c.Request.URL.Path
+		AbortWithProblem(c, problem)
+	}
+}
+
+// AbortWithProblem 直接输出 problem+json 并中止后续处理器；错误响应不允许被缓存。
+// detail 按协商出的语言翻译，因此标明 Content-Language 并追加 Vary: Accept-Language。
+// 经过 LegacyFormat 的旧版路由改为输出 LegacyError，状态码见 legacyStatus
+func AbortWithProblem(c *gin.Context, problem Problem) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Language", LocaleOf(c))
+	c.Writer.Header().Add("Vary", "Accept-Language") // 追加而不是覆盖，保留 CORS 等中间件设置的 Vary
+	if c.GetBool(legacyKey) {
+		c.Header("Content-Type", legacyContentType)
+		c.AbortWithStatusJSON(legacyStatus(problem.Status), LegacyError{Error: legacyMessage(problem)})
+		return
+	}
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// NewProblem 按状态码和错误码构造 Problem，detail 为英文描述
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "/problems/" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// LocalizedProblem 与 NewProblem 相同，但按当前请求的语言翻译 detail
+func LocalizedProblem(c *gin.Context, status int, code, detail string) Problem {
+	return localize(NewProblem(status, code, detail), LocaleOf(c))
+}
+
+func localize(problem Problem, locale string) Problem {
+	problem.Detail = i18n.Message(locale, problem.Code, problem.Detail)
+	return problem
+}
+
+func toProblem(err error, isBind bool, locale string) Problem {
+	return localize(rawProblem(err, isBind, locale), locale)
+}
+
+func rawProblem(err error, isBind bool, locale string) Problem {
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) {
+		status, ok := kindStatus[serviceErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		return NewProblem(status, serviceErr.Code, serviceErr.Message)
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		problem := NewProblem(http.StatusBadRequest, "validation_failed", "request validation failed")
+		trans := i18n.Translator(locale)
+		for _, fe := range validationErrs {
+			problem.Errors = append(problem.Errors, FieldViolation{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: fe.Translate(trans),
+			})
+		}
+		return problem
+	}
+
+	// 其余绑定错误：JSON 语法错误、类型不匹配、空请求体等
+	if isBind {
+		return NewProblem(http.StatusBadRequest, "malformed_request", "request body could not be parsed")
+	}
+
+	switch {
+	case errors.Is(err, auth.ErrMissingToken):
+		return NewProblem(http.StatusUnauthorized, "authentication_required", "authorization header required")
+	case errors.Is(err, auth.ErrInvalidToken):
+		return NewProblem(http.StatusUnauthorized, "invalid_token", "invalid token")
+	}
+
+	return NewProblem(http.StatusInternalServerError, "internal_error", "internal server error")
+}
+
+// setupValidator 配置 gin 使用的 validator：
+// 字段名使用 json 标签（如 title 而不是 Title），并注册中英文错误翻译
+func setupValidator(log *zap.Logger) {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	if err := i18n.RegisterValidator(v); err != nil {
+		log.Error("Failed to register validator translations", zap.Error(err))
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
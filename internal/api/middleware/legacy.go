@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// legacyContentType 旧版路由的 JSON 响应类型，不带 charset 参数（JSON 总是 UTF-8 编码，RFC 8259）
+const legacyContentType = "application/json"
+
+// LegacyFormat 让旧版路由保持改造前的响应格式：错误响应为 {"error": "..."} 而不是 problem+json，
+// JSON 响应的 Content-Type 为 application/json，与现有客户端（如 Postman 测试集）的断言一致
+func LegacyFormat() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(legacyKey, true)
+		// gin 只在 Content-Type 为空时写入带 charset 的默认值，这里预先设置即可覆盖所有 JSON 响应
+		c.Header("Content-Type", legacyContentType)
+		c.Next()
+	}
+}
+
+// legacyStatus 旧版错误的状态码与 v1 相同，只有 409 改回 400：改造前用户名或邮箱已被注册时返回 400
+func legacyStatus(status int) int {
+	if status == http.StatusConflict {
+		return http.StatusBadRequest
+	}
+	return status
+}
+
+// legacyMessage 旧版错误信息：校验失败时逐个列出字段错误，其余情况使用 detail
+func legacyMessage(problem Problem) string {
+	if len(problem.Errors) == 0 {
+		return problem.Detail
+	}
+	messages := make([]string, len(problem.Errors))
+	for i, violation := range problem.Errors {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
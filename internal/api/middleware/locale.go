@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"blogSystem/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const localeKey = "locale"
+
+// Locale 根据 Accept-Language 协商响应语言，结果保存在上下文中供错误处理使用；
+// 只有错误响应的内容随语言变化，Content-Language 与 Vary: Accept-Language 由 AbortWithProblem 输出
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(localeKey, locale)
+		c.Next()
+	}
+}
+
+// LocaleOf 返回当前请求协商出的语言，未经过 Locale 中间件时返回默认语言
+func LocaleOf(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+	return i18n.LocaleZH
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"blogSystem/pkg/metrics"
+	"crypto/subtle"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 把每个请求的次数与耗时记录到 m；未匹配任何路由的请求 route 记为 unmatched
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), time.Since(start))
+	}
+}
+
+// MetricsAccess 限制 /metrics 的访问：来源地址在 allow 网段内，或携带正确的 Bearer 令牌（token 非空时）。
+// 来源地址取 TCP 连接的对端地址，不信任 X-Forwarded-For
+func MetricsAccess(allow []netip.Prefix, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" {
+			given := c.GetHeader("Authorization")
+			if subtle.ConstantTimeCompare([]byte(given), []byte("Bearer "+token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+
+		if addr, err := netip.ParseAddr(c.RemoteIP()); err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range allow {
+				if prefix.Contains(addr) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		AbortWithProblem(c, LocalizedProblem(c, http.StatusForbidden, "metrics_forbidden", "metrics access denied"))
+	}
+}
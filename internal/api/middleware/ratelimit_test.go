@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"blogSystem/pkg/metrics"
+	"blogSystem/pkg/ratelimit"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newLimitedRouter 只挂限流中间件的路由；X-Test-User 头模拟 JWT 中间件设置的 userID
+func newLimitedRouter(limiter *RateLimiter, group string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			n, _ := strconv.ParseUint(id, 10, 64)
+			c.Set("userID", uint(n))
+		}
+	})
+	r.GET("/", limiter.Group(group), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func request(r http.Handler, ip, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":40000"
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiterRejectsWithHeaders(t *testing.T) {
+	m := metrics.New()
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(),
+		map[string]ratelimit.Limit{"write": ratelimit.PerPeriod(2, time.Minute, 2)}, m)
+	r := newLimitedRouter(limiter, "write")
+
+	for i, remaining := range []string{"1", "0"} {
+		w := request(r, "192.0.2.1", "")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: X-RateLimit-Limit %q", i+1, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d: X-RateLimit-Remaining %q, want %s", i+1, got, remaining)
+		}
+		if w.Header().Get("Retry-After") != "" {
+			t.Errorf("request %d: Retry-After on an allowed request", i+1)
+		}
+	}
+
+	w := request(r, "192.0.2.1", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit: status %d", w.Code)
+	}
+	// 每 30 秒补充一个令牌
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After %q, want 30", got)
+	}
+	if got := w.Header().Get("X-RateLimit-Reset"); got != "60" {
+		t.Errorf("X-RateLimit-Reset %q, want 60", got)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining %q, want 0", got)
+	}
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil || problem.Code != "rate_limited" {
+		t.Errorf("expected a rate_limited problem, got %s", w.Body)
+	}
+
+	scrape := httptest.NewRecorder()
+	m.Handler().ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(scrape.Body.String(), `blog_rate_limited_requests_total{group="write"} 1`) {
+		t.Error("rejected request was not counted")
+	}
+}
+
+func TestRateLimiterKeys(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(),
+		map[string]ratelimit.Limit{"write": ratelimit.PerPeriod(1, time.Minute, 1)}, nil)
+	r := newLimitedRouter(limiter, "write")
+
+	steps := []struct {
+		ip, user string
+		want     int
+	}{
+		{"192.0.2.1", "", http.StatusNoContent},
+		{"192.0.2.1", "", http.StatusTooManyRequests},
+		{"192.0.2.2", "", http.StatusNoContent},  // 匿名请求按 IP 计数
+		{"192.0.2.1", "7", http.StatusNoContent}, // 登录用户按用户计数，不受同一 IP 的匿名请求影响
+		{"192.0.2.9", "7", http.StatusTooManyRequests},
+		{"192.0.2.1", "8", http.StatusNoContent},
+	}
+	for i, s := range steps {
+		if got := request(r, s.ip, s.user).Code; got != s.want {
+			t.Fatalf("step %d (ip %s, user %q): status %d, want %d", i+1, s.ip, s.user, got, s.want)
+		}
+	}
+}
+
+func TestRateLimiterPolicies(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{}, nil)
+	r := newLimitedRouter(limiter, "read")
+
+	// 没有策略的分组不限流，也不输出限流头
+	for i := 0; i < 5; i++ {
+		w := request(r, "192.0.2.1", "")
+		if w.Code != http.StatusNoContent || w.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("unlimited group: status %d, headers %v", w.Code, w.Header())
+		}
+	}
+
+	// 热更新后立即生效
+	limiter.SetPolicies(map[string]ratelimit.Limit{"read": ratelimit.PerPeriod(1, time.Minute, 1)})
+	request(r, "192.0.2.1", "")
+	if got := request(r, "192.0.2.1", "").Code; got != http.StatusTooManyRequests {
+		t.Fatalf("after SetPolicies: status %d, want 429", got)
+	}
+}
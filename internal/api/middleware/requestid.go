@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"blogSystem/pkg/logger"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader 请求 ID 的请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 上游传入的请求 ID 超过该长度或包含非法字符时重新生成，避免污染日志
+const maxRequestIDLength = 128
+
+// RequestID 沿用上游（网关、调用方）传入的 X-Request-ID，没有时生成一个，并写回响应头；
+// 同时把由 log 派生、带 request_id 字段的 logger 存入请求 context，之后通过 logger.Ctx(c) 获取
+func RequestID(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+
+		ctx := logger.WithContext(c.Request.Context(), log.With(zap.String("request_id", id)))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequestIDOf 返回当前请求的 ID
+func RequestIDOf(c *gin.Context) string {
+	return c.GetString("requestID")
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
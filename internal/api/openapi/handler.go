@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed redoc.html
+var redocPage []byte
+
+// docsCSP /docs 页面的内容安全策略，放行 Redoc 脚本所在的 CDN 及其运行所需的内联样式与 worker
+const docsCSP = "default-src 'none'; script-src https://cdn.jsdelivr.net; style-src 'unsafe-inline' https://fonts.googleapis.com; " +
+	"font-src https://fonts.gstatic.com; img-src 'self' data: https:; connect-src 'self'; worker-src blob:; frame-ancestors 'none'"
+
+// Handler 输出 OpenAPI 文档与 Redoc 文档页面
+//
+//	文档要等所有路由注册完成后才能生成，因此先注册处理器，再通过 SetDocument 设置内容。
+type Handler struct {
+	doc Document
+}
+
+func (h *Handler) SetDocument(doc Document) {
+	h.doc = doc
+}
+
+// Spec 输出 /openapi.json；文档生成失败（未设置）时返回 503
+func (h *Handler) Spec(c *gin.Context) {
+	if h.doc == nil {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+	c.JSON(http.StatusOK, h.doc)
+}
+
+// Docs 输出 /docs 页面，覆盖 API 默认的严格内容安全策略
+func (h *Handler) Docs(c *gin.Context) {
+	c.Header("Content-Security-Policy", docsCSP)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", redocPage)
+}
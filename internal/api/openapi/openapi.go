@@ -0,0 +1,362 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMode 路由的认证要求
+type AuthMode int
+
+const (
+	AuthNone     AuthMode = iota // 无需认证
+	AuthOptional                 // 可选认证：携带令牌时返回更多信息
+	AuthRequired                 // 必须携带有效令牌
+)
+
+// Param 查询参数或路径参数
+type Param struct {
+	Name        string
+	In          string // query 或 path
+	Description string
+	Required    bool
+	Deprecated  bool // 仍然支持但不再推荐使用的参数
+	Schema      map[string]any
+}
+
+// Operation 描述一条路由，Method/Path 与 gin 注册时一致（如 /api/v1/posts/:id）
+//
+//	Request、Response 传入对应类型的零值，文档中的结构与约束从 json/binding 标签反射生成；
+//	Response 为 nil 时改用 ContentType 描述非 JSON 响应（如 XML、纯文本）；
+//	ErrorResponse 为 nil 时错误响应使用 Build 传入的 problem+json 结构，否则为 application/json 的该结构。
+type Operation struct {
+	Method        string
+	Path          string
+	Summary       string
+	Tags          []string
+	Auth          AuthMode
+	Query         []Param
+	Request       any
+	Success       int
+	Response      any
+	ContentType   string
+	Errors        []int
+	ErrorResponse any
+	Deprecated    bool
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Document OpenAPI 文档（直接序列化为 JSON）
+type Document map[string]any
+
+// Build 根据 gin 已注册的路由和路由描述生成 OpenAPI 3.1 文档
+//
+//	每条已注册的路由都必须有对应的 Operation，反之亦然；
+//	任何一边缺失都会返回错误，保证文档与路由同步。
+func Build(info Info, routes gin.RoutesInfo, ops []Operation, problem any) (Document, error) {
+	byKey := make(map[string]Operation, len(ops))
+	var errs []error
+	for _, op := range ops {
+		key := op.Method + " " + op.Path
+		if _, dup := byKey[key]; dup {
+			errs = append(errs, fmt.Errorf("duplicate operation %s", key))
+		}
+		byKey[key] = op
+	}
+
+	g := &generator{schemas: make(map[string]any)}
+	problemRef := g.schemaOf(reflect.TypeOf(problem))
+	paths := make(map[string]map[string]any)
+
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		seen[key] = true
+		op, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("route %s is not documented", key))
+			continue
+		}
+		path, params := convertPath(route.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+		paths[path][strings.ToLower(route.Method)] = g.operation(op, params, problemRef)
+	}
+	for key := range byKey {
+		if !seen[key] {
+			errs = append(errs, fmt.Errorf("operation %s has no matching route", key))
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return nil, errors.Join(errs...)
+	}
+
+	return Document{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				// 令牌原样放在 Authorization 头中，不带 Bearer 前缀
+				"token": map[string]any{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "Authorization",
+				},
+			},
+		},
+	}, nil
+}
+
+type generator struct {
+	schemas map[string]any
+}
+
+func (g *generator) operation(op Operation, pathParams []string, problemRef map[string]any) map[string]any {
+	out := map[string]any{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+	}
+	if len(op.Tags) > 0 {
+		out["tags"] = op.Tags
+	}
+	if op.Deprecated {
+		out["deprecated"] = true
+	}
+
+	switch op.Auth {
+	case AuthRequired:
+		out["security"] = []map[string][]string{{"token": {}}}
+	case AuthOptional:
+		out["security"] = []map[string][]string{{}, {"token": {}}}
+	}
+
+	var params []map[string]any
+	for _, name := range pathParams {
+		schema := map[string]any{"type": "string"}
+		if name == "id" {
+			schema = map[string]any{"type": "integer", "minimum": 1}
+		}
+		params = append(params, map[string]any{
+			"name": name, "in": "path", "required": true, "schema": schema,
+		})
+	}
+	for _, p := range op.Query {
+		in := p.In
+		if in == "" {
+			in = "query"
+		}
+		param := map[string]any{"name": p.Name, "in": in, "required": p.Required, "schema": p.Schema}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		if p.Deprecated {
+			param["deprecated"] = true
+		}
+		params = append(params, param)
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	if op.Request != nil {
+		out["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": g.schemaOf(reflect.TypeOf(op.Request))},
+			},
+		}
+	}
+
+	success := op.Success
+	if success == 0 {
+		success = http.StatusOK
+	}
+	responses := map[string]any{}
+	successResp := map[string]any{"description": http.StatusText(success)}
+	switch {
+	case op.Response != nil:
+		successResp["content"] = map[string]any{
+			"application/json": map[string]any{"schema": g.schemaOf(reflect.TypeOf(op.Response))},
+		}
+	case op.ContentType != "":
+		successResp["content"] = map[string]any{
+			op.ContentType: map[string]any{"schema": map[string]any{"type": "string"}},
+		}
+	}
+	responses[strconv.Itoa(success)] = successResp
+
+	errorCodes := append([]int(nil), op.Errors...)
+	if op.Auth == AuthRequired {
+		errorCodes = append(errorCodes, http.StatusUnauthorized)
+	}
+	errorCodes = append(errorCodes, http.StatusInternalServerError)
+	errorContent := map[string]any{"application/problem+json": map[string]any{"schema": problemRef}}
+	if op.ErrorResponse != nil {
+		errorContent = map[string]any{"application/json": map[string]any{"schema": g.schemaOf(reflect.TypeOf(op.ErrorResponse))}}
+	}
+	for _, code := range errorCodes {
+		responses[strconv.Itoa(code)] = map[string]any{
+			"description": http.StatusText(code),
+			"content":     errorContent,
+		}
+	}
+	out["responses"] = responses
+	return out
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf 返回类型对应的 JSON Schema；结构体登记到 components 并返回 $ref
+func (g *generator) schemaOf(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = map[string]any{} // 先占位，防止递归类型死循环
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+		// 与 encoding/json 一致，没有 json 名称的嵌入结构体的字段平铺到外层
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			embedded := g.structSchema(field.Type)
+			for k, v := range embedded["properties"].(map[string]any) {
+				properties[k] = v
+			}
+			if r, ok := embedded["required"].([]string); ok {
+				required = append(required, r...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schemaOf(field.Type)
+		if applyBinding(schema, field.Tag.Get("binding")) {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+
+	out := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+// applyBinding 把 binding 标签中的约束写入 schema，返回字段是否必填
+func applyBinding(schema map[string]any, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		n, numErr := strconv.Atoi(param)
+		isString := schema["type"] == "string"
+
+		switch {
+		case name == "required":
+			required = true
+		case name == "email":
+			schema["format"] = "email"
+		case name == "min" && numErr == nil && isString:
+			schema["minLength"] = n
+		case name == "max" && numErr == nil && isString:
+			schema["maxLength"] = n
+		case name == "min" && numErr == nil:
+			schema["minimum"] = n
+		case name == "max" && numErr == nil:
+			schema["maximum"] = n
+		}
+	}
+	return required
+}
+
+// convertPath 把 gin 路由参数（:id、*path）转换为 OpenAPI 格式（{id}），并返回参数名
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, seg := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '.' || r == '-' || r == ':' || r == '*'
+	}) {
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
@@ -1,50 +1,270 @@
 package api
 
 import (
+	"blogSystem/config"
 	"blogSystem/internal/api/handlers"
+	"blogSystem/internal/api/middleware"
+	"blogSystem/internal/api/openapi"
+	"blogSystem/internal/app"
+	repogorm "blogSystem/internal/repository/gorm"
 	"blogSystem/internal/service"
-	"blogSystem/pkg/auth"
-	"blogSystem/pkg/database"
+	"blogSystem/pkg/health"
+	"blogSystem/pkg/ratelimit"
+	"blogSystem/pkg/tracing"
+	"net/http"
+	"time"
 
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
 )
 
-func NewRouter() *gin.Engine {
-	r := gin.Default()
+// 旧版（非 RESTful）路由的弃用时间与下线时间
+var (
+	legacyDeprecatedAt = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	legacySunset       = time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// NewRouter 注册全部路由，依赖全部来自 a；readiness 为就绪检查注册表，由调用方注册检查并在关闭时标记 draining；
+// 支持热更新的服务向 reloader 订阅配置变化
+func NewRouter(a *app.App, readiness *health.Registry, reloader *config.Reloader) *gin.Engine {
+	cfg := a.Config
+	// 生产环境关闭 gin 的调试输出；访问日志与 panic 统一由 zap 输出
+	if cfg.Server.Env == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	// 允许直接把 *gin.Context 当作 context 传给 logger.Ctx 等函数，取值时回落到请求 context
+	r.ContextWithFallback = true
+	// 只信任配置的反向代理转发的 X-Forwarded-For，否则客户端可以伪造 IP 绕过限流
+	_ = r.SetTrustedProxies(cfg.Server.TrustedProxies) // 已在 config.Load 中校验
+	if a.Tracing != nil {
+		// 解析请求头中的 W3C traceparent 并为每个请求创建根 span；探针与指标抓取不产生链路
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName,
+			otelgin.WithTracerProvider(a.Tracing),
+			otelgin.WithPropagators(tracing.Propagator()),
+			otelgin.WithGinFilter(func(c *gin.Context) bool {
+				switch c.FullPath() {
+				case "/healthz", "/readyz", "/metrics":
+					return false
+				}
+				return true
+			}),
+		))
+	}
+	r.Use(middleware.RequestID(a.Log), middleware.AccessLog())
+	if cfg.Metrics.Enabled {
+		r.Use(middleware.Metrics(a.Metrics))
+	}
+	// 跨域：只有配置了前端来源时才处理，来源不在列表中的跨域请求返回 403；预检请求由它直接响应
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		r.Use(cors.New(cors.Config{
+			AllowOrigins:     cfg.CORS.AllowOrigins,
+			AllowWildcard:    true,
+			AllowMethods:     cfg.CORS.AllowMethods,
+			AllowHeaders:     cfg.CORS.AllowHeaders,
+			ExposeHeaders:    cfg.CORS.ExposeHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+	hstsMaxAge := cfg.Security.HSTSMaxAge
+	if cfg.Server.Env != "production" {
+		hstsMaxAge = 0 // 本地开发通常使用 HTTP，HSTS 会让浏览器在之后强制使用 HTTPS 访问 localhost
+	}
+	r.Use(middleware.SecurityHeaders(cfg.Security.CSP, cfg.Security.ReferrerPolicy, hstsMaxAge), middleware.NoStore())
+	r.Use(middleware.Recovery(), middleware.Locale(), middleware.ErrorHandler(a.Log))
+	if cfg.Security.CookieSessions {
+		r.Use(middleware.CSRF(cfg.Security.SessionCookie, cfg.Server.Env == "production"))
+	}
+	r.NoRoute(func(c *gin.Context) {
+		middleware.AbortWithProblem(c, middleware.LocalizedProblem(c, http.StatusNotFound, "route_not_found", "route not found"))
+	})
+
+	// 按路由的限流与 HTTP 缓存策略；未启用限流时各分组的中间件直接放行，未指定缓存策略的路由保持 no-store
+	// 文章与评论的响应随登录用户变化（can_edit 等），Vary 列出携带凭据的请求头
+	credentials := []string{"Authorization"}
+	if cfg.Security.CookieSessions {
+		credentials = append(credentials, "Cookie")
+	}
+	mw := routeMiddleware{
+		limit:       func(string) gin.HandlerFunc { return func(c *gin.Context) { c.Next() } },
+		detailCache: middleware.CacheControl(cfg.HTTPCache.DetailMaxAge, credentials...),
+		listCache:   middleware.CacheControl(cfg.HTTPCache.ListMaxAge, credentials...),
+	}
+	if cfg.RateLimit.Enabled {
+		limiter := middleware.NewRateLimiter(a.Limits, ratePolicies(cfg), a.Metrics)
+		reloader.Subscribe(func(_, cfg *config.Config) { limiter.SetPolicies(ratePolicies(cfg)) })
+		mw.limit = limiter.Group
+	}
 
-	// 获取数据库实例
-	db := database.GetDB()
+	// 初始化仓储
+	userRepo := repogorm.NewUserRepository(a.DB)
+	postRepo := repogorm.NewPostRepository(a.DB)
+	commentRepo := repogorm.NewCommentRepository(a.DB)
 
 	// 初始化服务
-	authService := service.NewAuthService(db)
-	postService := service.NewPostService(db)
-	commentService := service.NewCommentService(db)
+	authService := service.NewAuthService(userRepo, a.Tokens)
+	authService.SetMetrics(a.Metrics)
+	authService.SetRegistrationOpen(cfg.Auth.Registration == "open")
+	reloader.Subscribe(func(old, cfg *config.Config) {
+		if cfg.Auth.Registration != old.Auth.Registration {
+			authService.SetRegistrationOpen(cfg.Auth.Registration == "open")
+		}
+	})
+	userService := service.NewUserService(userRepo)
+	mw.requireAuth = a.Tokens.JWTMiddleware(userService.ValidateToken)
+	mw.optionalAuth = a.Tokens.OptionalJWTMiddleware(userService.ValidateToken)
+	postService := service.NewPostService(postRepo)
+	commentService := service.NewCommentService(commentRepo, postRepo)
+	postService.SetCache(a.Cache)
+	commentService.SetCache(a.Cache)
+	postService.SetMetrics(a.Metrics)
+	commentService.SetMetrics(a.Metrics)
+	sitemapService := service.NewSitemapService(postRepo, cfg.Site.BaseURL, cfg.Site.PostPath, cfg.Site.AuthorPath)
+	sitemapService.SetRefresh(cfg.Site.SitemapRefresh)
+	postService.AddObserver(sitemapService)
 
 	// 初始化服务器
 	authHandler := handlers.NewAuthHandler(authService)
+	pages := handlers.PageLimits{
+		DefaultSize: cfg.Pagination.DefaultSize,
+		MaxSize:     cfg.Pagination.MaxSize,
+	}
+	postHandler := handlers.NewPostHandler(postService, pages)
+	commentHandler := handlers.NewCommentHandler(commentService, pages)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService, cfg.Site.RobotsDisallow)
+	healthHandler := handlers.NewHealthHandler(readiness, userService)
+	adminHandler := handlers.NewAdminHandler(a.LogLevel, userService, pages)
 
-	// 公共路由
-	r.POST("/register", authHandler.Register)
-	r.POST("/login", authHandler.Login)
+	// 存活与就绪探针
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", mw.optionalAuth, healthHandler.Readyz)
+
+	// 站点地图与爬虫规则
+	sitemapCache := middleware.CacheControl(cfg.HTTPCache.SitemapMaxAge)
+	r.GET("/sitemap.xml", sitemapCache, sitemapHandler.Sitemap)
+	r.GET("/sitemaps/:page", sitemapCache, sitemapHandler.SitemapPage)
+	r.GET("/robots.txt", sitemapCache, sitemapHandler.Robots)
 
-	// 需要认证的路由
-	authGroup := r.Group("/")
-	authGroup.Use(auth.JWTMiddleware())
+	// Prometheus 指标，访问来源由 METRICS_ALLOW / METRICS_TOKEN 限制
+	if cfg.Metrics.Enabled {
+		allow, _ := config.ParsePrefixes(cfg.Metrics.Allow) // 已在 config.Load 中校验
+		r.GET("/metrics", middleware.MetricsAccess(allow, cfg.Metrics.Token), gin.WrapH(a.Metrics.Handler()))
+	}
+
+	// 运维管理接口，仅管理员可用
+	admin := r.Group("/admin")
+	admin.Use(mw.requireAuth, middleware.RequireAdmin(userService))
 	{
-		// 文章路由
-		postHandler := handlers.NewPostHandler(postService)
-		authGroup.POST("/createPost", postHandler.Create)
-		authGroup.GET("/getPostById/:id", postHandler.GetById)
-		authGroup.POST("/UpdateById/:id", postHandler.Update)
-		authGroup.GET("/DeleteById/:id", postHandler.Delete)
-		authGroup.GET("/listPosts", postHandler.List)
+		admin.PUT("/log-level", adminHandler.SetLogLevel)
+		admin.GET("/users", adminHandler.ListUsers)
+	}
 
-		// 评论路由
-		commentHandler := handlers.NewCommentHandler(commentService)
-		authGroup.POST("/creatComment/:id", commentHandler.Create)
-		authGroup.GET("/getCommentById/:id", commentHandler.GetByPostID)
-		authGroup.GET("/deleteCommentById/:id", commentHandler.Delete)
+	// API 文档
+	docsHandler := &openapi.Handler{}
+	r.GET("/openapi.json", docsHandler.Spec)
+	r.GET("/docs", docsHandler.Docs)
+
+	// v1 API：面向资源的路由，使用标准 HTTP 方法
+	v1 := r.Group("/api/v1")
+	{
+		// 注册与登录：按客户端 IP 限流，防止批量注册与暴力破解
+		authGroup := v1.Group("/auth")
+		authGroup.Use(mw.limit("auth"))
+		{
+			authGroup.POST("/register", authHandler.Register)
+			authGroup.POST("/login", authHandler.Login)
+		}
+
+		// 只读路由：匿名可访问
+		public := v1.Group("")
+		public.Use(mw.optionalAuth, mw.limit("read"))
+		{
+			public.GET("/posts", mw.listCache, postHandler.List)
+			public.GET("/posts/search", mw.listCache, postHandler.Search)
+			public.GET("/posts/:id", mw.detailCache, postHandler.GetById)
+			public.GET("/posts/:id/comments", mw.listCache, commentHandler.GetByPostID)
+			public.GET("/users/:id/posts", mw.listCache, postHandler.ListByAuthor)
+		}
+
+		// 写操作需要认证
+		authed := v1.Group("")
+		authed.Use(mw.requireAuth, mw.limit("write"))
+		{
+			authed.POST("/posts", postHandler.Create)
+			authed.PATCH("/posts/:id", postHandler.Update)
+			authed.DELETE("/posts/:id", postHandler.Delete)
+			authed.POST("/posts/:id/comments", commentHandler.Create)
+			authed.DELETE("/comments/:id", commentHandler.Delete)
+		}
+	}
+
+	registerLegacyRoutes(r, mw, authHandler, postHandler, commentHandler, handlers.NewLegacyHandler(postService, commentService, pages))
+
+	// 所有路由注册完成后生成文档；有路由未在 spec.go 中登记属于编程错误，由 routes_test.go 在测试中拦截。
+	// 运行时只记录错误并让 /openapi.json 返回 503，不影响 API 本身
+	doc, err := openapi.Build(apiInfo, r.Routes(), operations(cfg), problemSchema)
+	if err != nil {
+		a.Log.Error("OpenAPI document is out of sync with the router", zap.Error(err))
 	}
+	docsHandler.SetDocument(doc)
 
 	return r
 }
+
+// ratePolicies 各限流分组的策略
+func ratePolicies(cfg *config.Config) map[string]ratelimit.Limit {
+	limit := func(p config.RatePolicy) ratelimit.Limit {
+		return ratelimit.PerPeriod(p.Requests, p.Period, p.Burst)
+	}
+	return map[string]ratelimit.Limit{
+		"auth":  limit(cfg.RateLimit.Auth),
+		"write": limit(cfg.RateLimit.Write),
+		"read":  limit(cfg.RateLimit.Read),
+	}
+}
+
+// routeMiddleware v1 与旧版路由共用的按路由中间件
+type routeMiddleware struct {
+	requireAuth  gin.HandlerFunc                    // 必须认证，每次请求校验账号状态与令牌版本
+	optionalAuth gin.HandlerFunc                    // 可选认证，令牌无效时按匿名访客处理
+	limit        func(group string) gin.HandlerFunc // 按分组限流
+	detailCache  gin.HandlerFunc                    // 文章详情的 Cache-Control
+	listCache    gin.HandlerFunc                    // 列表的 Cache-Control
+}
+
+// registerLegacyRoutes 注册旧版路由，仅为兼容已有客户端（如 Postman 测试集）保留，
+// 响应会携带 Deprecation/Sunset 头并指向对应的 v1 路由；限流分组与缓存策略与对应的 v1 路由相同。
+// 响应结构保持改造前的样子：与 v1 不同的读取接口由 LegacyHandler 处理，
+// 其余接口的响应本来就与 v1 相同，直接复用 v1 处理器；TestPostmanCollectionAgainstLegacyRoutes 回放测试集验证兼容性
+func registerLegacyRoutes(r *gin.Engine, mw routeMiddleware,
+	authHandler *handlers.AuthHandler, postHandler *handlers.PostHandler, commentHandler *handlers.CommentHandler,
+	legacyHandler *handlers.LegacyHandler) {
+	deprecated := func(successor string) gin.HandlerFunc {
+		return middleware.Deprecated(legacyDeprecatedAt, legacySunset, successor)
+	}
+
+	authLimit := mw.limit("auth")
+	optionalAuth, readLimit := mw.optionalAuth, mw.limit("read")
+	requireAuth, writeLimit := mw.requireAuth, mw.limit("write")
+	// 错误响应保持 {"error": "..."}，包括认证失败与限流
+	legacy := r.Group("", middleware.LegacyFormat())
+
+	// 公共路由
+	legacy.POST("/register", deprecated("/api/v1/auth/register"), authLimit, authHandler.Register)
+	legacy.POST("/login", deprecated("/api/v1/auth/login"), authLimit, authHandler.Login)
+
+	// 文章路由
+	legacy.POST("/createPost", deprecated("/api/v1/posts"), requireAuth, writeLimit, postHandler.Create)
+	legacy.GET("/getPostById/:id", deprecated("/api/v1/posts/:id"), optionalAuth, readLimit, mw.detailCache, legacyHandler.GetPost)
+	legacy.POST("/UpdateById/:id", deprecated("/api/v1/posts/:id"), requireAuth, writeLimit, postHandler.Update)
+	legacy.GET("/DeleteById/:id", deprecated("/api/v1/posts/:id"), requireAuth, writeLimit, postHandler.Delete)
+	legacy.GET("/listPosts", deprecated("/api/v1/posts"), optionalAuth, readLimit, mw.listCache, legacyHandler.ListPosts)
+
+	// 评论路由
+	legacy.POST("/creatComment/:id", deprecated("/api/v1/posts/:id/comments"), requireAuth, writeLimit, commentHandler.Create)
+	legacy.GET("/getCommentById/:id", deprecated("/api/v1/posts/:id/comments"), optionalAuth, readLimit, mw.listCache, legacyHandler.Comments)
+	legacy.GET("/deleteCommentById/:id", deprecated("/api/v1/comments/:id"), requireAuth, writeLimit, commentHandler.Delete)
+}
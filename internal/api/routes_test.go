@@ -0,0 +1,85 @@
+package api
+
+import (
+	"blogSystem/config"
+	"blogSystem/internal/api/openapi"
+	"blogSystem/internal/app"
+	"blogSystem/pkg/database"
+	"blogSystem/pkg/health"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestApp 创建使用内存 SQLite 的应用容器，overrides 为额外的 key=value 配置
+func newTestApp(t *testing.T, overrides ...string) *app.App {
+	t.Helper()
+	flags := config.Flags{Set: append([]string{
+		"db.driver=sqlite",
+		"db.dsn=:memory:",
+		"jwt.secret=" + strings.Repeat("s", 32),
+		"log.level=error",
+	}, overrides...)}
+	cfg, err := config.Load(flags)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	a, err := app.New(cfg)
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
+
+// migrateTestDB 在测试应用的内存数据库上执行全部迁移
+func migrateTestDB(t *testing.T, a *app.App) {
+	t.Helper()
+	migrator, err := database.NewMigrator(a.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestRouter 按 a 的配置注册全部路由
+func newTestRouter(t *testing.T, a *app.App) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	return NewRouter(a, health.NewRegistry(time.Second), config.NewReloader(config.Flags{}, a.Config))
+}
+
+// TestOpenAPISpecMatchesRouter 每条路由都必须在 spec.go 中登记，反之亦然
+func TestOpenAPISpecMatchesRouter(t *testing.T) {
+	cases := map[string][]string{
+		"defaults":     nil,
+		"optional off": {"metrics.enabled=false", "ratelimit.enabled=false", "cache.enabled=false"},
+		"cors":         {"cors.allow_origins=https://blog.example.com"},
+	}
+	for name, overrides := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := newTestApp(t, overrides...)
+			r := newTestRouter(t, a)
+			if _, err := openapi.Build(apiInfo, r.Routes(), operations(a.Config), problemSchema); err != nil {
+				t.Fatalf("openapi spec is out of sync with the router:\n%v", err)
+			}
+		})
+	}
+}
+
+// TestOpenAPISpecReportsUndocumentedRoute 未登记的路由会让文档生成失败
+func TestOpenAPISpecReportsUndocumentedRoute(t *testing.T) {
+	a := newTestApp(t)
+	r := newTestRouter(t, a)
+	r.GET("/undocumented", func(*gin.Context) {})
+
+	_, err := openapi.Build(apiInfo, r.Routes(), operations(a.Config), problemSchema)
+	if err == nil || !strings.Contains(err.Error(), "GET /undocumented") {
+		t.Fatalf("expected an error for GET /undocumented, got %v", err)
+	}
+}
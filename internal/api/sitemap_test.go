@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRobots(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides []string
+		want      string
+	}{
+		{"default", nil, "User-agent: *\nDisallow:\n\nSitemap: https://blog.example.com/sitemap.xml\n"},
+		{"disallow", []string{"site.robots_disallow=/createPost,/UpdateById"},
+			"User-agent: *\nDisallow: /createPost\nDisallow: /UpdateById\n\nSitemap: https://blog.example.com/sitemap.xml\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newTestApp(t, append([]string{"site.base_url=https://blog.example.com"}, tc.overrides...)...)
+			r := newTestRouter(t, a)
+
+			w := do(r, http.MethodGet, "/robots.txt", "", "")
+			if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+				t.Fatalf("expected 200 text/plain, got %d %q", w.Code, w.Header().Get("Content-Type"))
+			}
+			if got := w.Body.String(); got != tc.want {
+				t.Errorf("robots.txt:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestSitemapLinksAreServed 默认配置下 sitemap 中的文章与作者页面都是实际可访问的路由
+func TestSitemapLinksAreServed(t *testing.T) {
+	a := newTestApp(t, "ratelimit.enabled=false", "site.base_url=https://blog.example.com")
+	migrateTestDB(t, a)
+	r := newTestRouter(t, a)
+	seedLegacyData(t, r)
+
+	w := do(r, http.MethodGet, "/sitemap.xml", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("sitemap: %d %s", w.Code, w.Body)
+	}
+	var sitemap struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &sitemap); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, u := range sitemap.URLs {
+		paths = append(paths, strings.TrimPrefix(u.Loc, "https://blog.example.com"))
+	}
+	if want := []string{"/api/v1/posts/1", "/api/v1/users/1/posts"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("sitemap paths %v, want %v", paths, want)
+	}
+	for _, path := range paths {
+		if w := do(r, http.MethodGet, path, "", ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s: %d %s", path, w.Code, w.Body)
+		}
+	}
+	if body := do(r, http.MethodGet, "/api/v1/users/1/posts", "", "").Body.String(); !strings.Contains(body, `"title":"hello"`) {
+		t.Errorf("author page does not list the author's post: %s", body)
+	}
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"blogSystem/config"
+	"blogSystem/internal/api/handlers"
+	"blogSystem/internal/api/middleware"
+	"blogSystem/internal/api/openapi"
+	"blogSystem/pkg/health"
+	"net/http"
+	"strings"
+)
+
+// apiInfo OpenAPI 文档基本信息
+var apiInfo = openapi.Info{
+	Title:   "blogSystem API",
+	Version: "1.0.0",
+	Description: "个人博客系统后端 API。需要认证的接口把登录返回的 token 原样放入 Authorization 请求头；" +
+		"错误统一以 application/problem+json 返回，code 字段为稳定的错误码（已弃用的旧版路由仍返回 {\"error\": \"...\"}）。",
+}
+
+// pageQuery 列表接口共用的分页参数；翻页时直接使用响应中的 next/prev 链接（或 Link 响应头）
+var pageQuery = []openapi.Param{
+	{Name: "cursor", Description: "分页游标，取自上一次响应的 next/prev 链接，不要自行构造", Schema: map[string]any{"type": "string"}},
+	{Name: "size", Description: "每页条数", Schema: map[string]any{"type": "integer", "minimum": 1, "maximum": 100, "default": 10}},
+	{Name: "total", Description: "是否统计总条数，传 false 可跳过计数查询", Schema: map[string]any{"type": "boolean", "default": true}},
+	{Name: "page", Description: "页码，从 1 开始；按偏移量翻页，深分页较慢，仅为兼容保留，请改用 cursor", Deprecated: true,
+		Schema: map[string]any{"type": "integer", "minimum": 1, "default": 1}},
+}
+
+// legacyPageQuery 旧版文章列表的分页参数，只支持页码
+var legacyPageQuery = []openapi.Param{
+	{Name: "page", Description: "页码，从 1 开始", Schema: map[string]any{"type": "integer", "minimum": 1, "default": 1}},
+	{Name: "size", Description: "每页条数", Schema: map[string]any{"type": "integer", "minimum": 1, "maximum": 100, "default": 10}},
+}
+
+// v1Operations /api/v1 路由的文档描述，新增路由时必须同步在这里登记，否则启动时会报错
+var v1Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/register", Summary: "用户注册（auth.registration=closed 时返回 403）", Tags: []string{"auth"},
+		Request: handlers.RegisterRequest{}, Success: http.StatusCreated, Response: handlers.RegisterResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/login", Summary: "用户登录", Tags: []string{"auth"},
+		Request: handlers.LoginRequest{}, Response: handlers.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/posts", Summary: "文章列表", Tags: []string{"posts"},
+		Auth: openapi.AuthOptional, Query: pageQuery, Response: handlers.PostListResponse{},
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/users/:id/posts", Summary: "用户发表的文章列表（作者页面）", Tags: []string{"posts"},
+		Auth: openapi.AuthOptional, Query: pageQuery, Response: handlers.PostListResponse{},
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/posts/search", Summary: "全文检索文章", Tags: []string{"posts"},
+		Auth: openapi.AuthOptional, Response: handlers.PostListResponse{},
+		Query: append([]openapi.Param{
+			{Name: "q", Description: "检索关键词", Required: true, Schema: map[string]any{"type": "string", "minLength": 1}},
+		}, pageQuery...),
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/posts", Summary: "创建文章", Tags: []string{"posts"},
+		Auth: openapi.AuthRequired, Request: handlers.CreatePostRequest{},
+		Success: http.StatusCreated, Response: handlers.CreatePostResponse{},
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/posts/:id", Summary: "文章详情（含评论）", Tags: []string{"posts"},
+		Auth: openapi.AuthOptional, Response: handlers.PostResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/v1/posts/:id", Summary: "更新文章（仅作者）", Tags: []string{"posts"},
+		Auth: openapi.AuthRequired, Request: handlers.UpdatePostRequest{}, Response: handlers.MessageResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/posts/:id", Summary: "删除文章（仅作者）", Tags: []string{"posts"},
+		Auth: openapi.AuthRequired, Response: handlers.MessageResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/posts/:id/comments", Summary: "文章评论列表", Tags: []string{"comments"},
+		Auth: openapi.AuthOptional, Query: pageQuery, Response: handlers.CommentListResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/posts/:id/comments", Summary: "发表评论", Tags: []string{"comments"},
+		Auth: openapi.AuthRequired, Request: handlers.CreateCommentRequest{},
+		Success: http.StatusCreated, Response: handlers.CreateCommentResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/comments/:id", Summary: "删除评论（仅评论者）", Tags: []string{"comments"},
+		Auth: openapi.AuthRequired, Response: handlers.MessageResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+}
+
+// siteOperations 站点地图、爬虫规则、文档页面与健康检查
+var siteOperations = []openapi.Operation{
+	{Method: http.MethodGet, Path: "/healthz", Summary: "存活检查", Tags: []string{"health"}, Response: health.Report{}},
+	{
+		Method: http.MethodGet, Path: "/readyz", Summary: "就绪检查，未就绪或正在关闭时返回 503", Tags: []string{"health"},
+		Auth: openapi.AuthOptional, Response: health.Report{},
+		Query: []openapi.Param{
+			{Name: "verbose", Description: "返回每项检查的详情，仅管理员可用", Schema: map[string]any{"type": "boolean"}},
+		},
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+	{Method: http.MethodGet, Path: "/sitemap.xml", Summary: "站点地图（超过 5 万条时为索引）", Tags: []string{"site"}, ContentType: "application/xml"},
+	{Method: http.MethodGet, Path: "/sitemaps/:page", Summary: "分页站点地图，如 /sitemaps/2.xml", Tags: []string{"site"}, ContentType: "application/xml", Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/robots.txt", Summary: "爬虫规则", Tags: []string{"site"}, ContentType: "text/plain"},
+	{Method: http.MethodGet, Path: "/openapi.json", Summary: "OpenAPI 文档", Tags: []string{"site"}, ContentType: "application/json"},
+	{Method: http.MethodGet, Path: "/docs", Summary: "API 文档页面", Tags: []string{"site"}, ContentType: "text/html"},
+}
+
+// metricsOperation Prometheus 指标，仅在 METRICS_ENABLED 时注册
+var metricsOperation = openapi.Operation{
+	Method: http.MethodGet, Path: "/metrics", Summary: "Prometheus 指标（限制来源地址或 Bearer 令牌）", Tags: []string{"health"},
+	ContentType: "text/plain", Errors: []int{http.StatusForbidden},
+}
+
+// adminOperations 运维管理接口，仅管理员可用
+var adminOperations = []openapi.Operation{
+	{
+		Method: http.MethodPut, Path: "/admin/log-level", Summary: "运行时调整日志级别（重启后恢复为配置值）", Tags: []string{"admin"},
+		Auth: openapi.AuthRequired, Request: handlers.LogLevelRequest{}, Response: handlers.LogLevelResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/users", Summary: "用户列表（按注册时间倒序）", Tags: []string{"admin"},
+		Auth: openapi.AuthRequired, Query: pageQuery, Response: handlers.UserListResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+	},
+}
+
+// legacyOperations 已弃用的旧版路由，请求结构与对应的 v1 路由相同，错误响应为 {"error": "..."}；
+// 部分读取接口保持改造前的响应结构
+func legacyOperations() []openapi.Operation {
+	v1 := make(map[string]openapi.Operation, len(v1Operations))
+	for _, op := range v1Operations {
+		v1[op.Method+" "+op.Path] = op
+	}
+	// response 非 nil 时响应结构与 v1 不同（见 handlers.LegacyHandler），这些接口不支持 v1 的游标分页参数
+	legacy := func(method, path, successor string, response any, query []openapi.Param) openapi.Operation {
+		op := v1[successor]
+		if response != nil {
+			op.Response, op.Query = response, query
+		}
+		op.Method, op.Path = method, path
+		op.Tags = []string{"legacy"}
+		op.Summary += "（已弃用，请使用 " + successor + "）"
+		op.Deprecated = true
+		op.ErrorResponse = middleware.LegacyError{}
+		return op
+	}
+	return []openapi.Operation{
+		legacy(http.MethodPost, "/register", "POST /api/v1/auth/register", nil, nil),
+		legacy(http.MethodPost, "/login", "POST /api/v1/auth/login", nil, nil),
+		legacy(http.MethodPost, "/createPost", "POST /api/v1/posts", nil, nil),
+		legacy(http.MethodGet, "/getPostById/:id", "GET /api/v1/posts/:id", handlers.LegacyPostResponse{}, nil),
+		legacy(http.MethodPost, "/UpdateById/:id", "PATCH /api/v1/posts/:id", nil, nil),
+		legacy(http.MethodGet, "/DeleteById/:id", "DELETE /api/v1/posts/:id", nil, nil),
+		legacy(http.MethodGet, "/listPosts", "GET /api/v1/posts", handlers.LegacyPostList{}, legacyPageQuery),
+		legacy(http.MethodPost, "/creatComment/:id", "POST /api/v1/posts/:id/comments", nil, nil),
+		legacy(http.MethodGet, "/getCommentById/:id", "GET /api/v1/posts/:id/comments", []handlers.LegacyComment{}, nil),
+		legacy(http.MethodGet, "/deleteCommentById/:id", "DELETE /api/v1/comments/:id", nil, nil),
+	}
+}
+
+// operations 返回所有路由的文档描述
+func operations(cfg *config.Config) []openapi.Operation {
+	ops := append([]openapi.Operation{}, v1Operations...)
+	ops = append(ops, siteOperations...)
+	ops = append(ops, adminOperations...)
+	if cfg.Metrics.Enabled {
+		ops = append(ops, metricsOperation)
+	}
+	ops = append(ops, legacyOperations()...)
+
+	for i := range ops {
+		// 启用限流时 v1 与旧版路由超出限额返回 429
+		if cfg.RateLimit.Enabled && (strings.HasPrefix(ops[i].Path, "/api/v1/") || ops[i].Deprecated) {
+			ops[i].Errors = append(append([]int{}, ops[i].Errors...), http.StatusTooManyRequests)
+		}
+
+		// 分页参数的默认值与上限来自配置
+		if len(ops[i].Query) == 0 {
+			continue
+		}
+		query := make([]openapi.Param, len(ops[i].Query))
+		for j, param := range ops[i].Query {
+			if param.Name == "size" {
+				param.Schema = map[string]any{"type": "integer", "minimum": 1,
+					"maximum": cfg.Pagination.MaxSize, "default": cfg.Pagination.DefaultSize}
+			}
+			query[j] = param
+		}
+		ops[i].Query = query
+	}
+	return ops
+}
+
+// problemSchema 错误响应的结构
+var problemSchema = middleware.Problem{}
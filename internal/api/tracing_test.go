@@ -0,0 +1,78 @@
+package api
+
+import (
+	"blogSystem/internal/domain"
+	repogorm "blogSystem/internal/repository/gorm"
+	"blogSystem/pkg/tracing"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTracingJoinsRequestServiceAndGormSpans 一个请求的 HTTP span、服务层 span 与 GORM span 属于同一条链路，
+// 并沿用请求头中的 traceparent
+func TestTracingJoinsRequestServiceAndGormSpans(t *testing.T) {
+	a := newTestApp(t, "tracing.enabled=true", "cache.enabled=false")
+	migrateTestDB(t, a)
+	user := &domain.User{Username: "alice", Password: "x", Email: "alice@example.com", Role: domain.RoleUser}
+	if err := repogorm.NewUserRepository(a.DB).Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	post := &domain.Post{Title: "hello", Content: "hello world!", UserID: user.ID}
+	if err := repogorm.NewPostRepository(a.DB).Create(context.Background(), post); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "blog-test", 1)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	if err := a.EnableTracing(tp); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRouter(t, a)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get post: %d %s", w.Code, w.Body)
+	}
+
+	spans := exporter.GetSpans()
+	find := func(prefix string) tracetest.SpanStub {
+		t.Helper()
+		for _, span := range spans {
+			if strings.HasPrefix(span.Name, prefix) {
+				return span
+			}
+		}
+		t.Fatalf("no span named %q* among %d spans", prefix, len(spans))
+		return tracetest.SpanStub{}
+	}
+	request := find("GET /api/v1/posts/:id")
+	svc := find("PostService.GetByID")
+	query := find("gorm.query posts")
+
+	for _, span := range []tracetest.SpanStub{request, svc, query} {
+		if got := span.SpanContext.TraceID().String(); got != traceID {
+			t.Errorf("span %q: trace id %s, want %s from traceparent", span.Name, got, traceID)
+		}
+	}
+	if request.SpanKind != trace.SpanKindServer {
+		t.Errorf("request span kind %v, want server", request.SpanKind)
+	}
+	if svc.Parent.SpanID() != request.SpanContext.SpanID() {
+		t.Errorf("service span is not a child of the request span")
+	}
+	if query.Parent.SpanID() != svc.SpanContext.SpanID() {
+		t.Errorf("gorm span is not a child of the service span")
+	}
+}
@@ -0,0 +1,171 @@
+// Package app 应用容器：由 Config 构建日志、数据库、令牌签发器、指标、限流存储与缓存，再注入路由、服务与命令行，
+// 不依赖包级全局变量，同一进程中可以同时运行多个实例（如测试中各自使用独立的 SQLite 数据库）
+package app
+
+import (
+	"blogSystem/config"
+	"blogSystem/pkg/auth"
+	"blogSystem/pkg/cache"
+	"blogSystem/pkg/database"
+	"blogSystem/pkg/logger"
+	"blogSystem/pkg/metrics"
+	"blogSystem/pkg/ratelimit"
+	"blogSystem/pkg/tracing"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type App struct {
+	Config   *config.Config
+	Log      *zap.Logger
+	LogLevel zap.AtomicLevel // 运行时可调整的日志级别，见 PUT /admin/log-level 与配置热更新
+	DB       *gorm.DB
+	Tokens   *auth.TokenIssuer
+	Metrics  *metrics.Metrics     // 本实例的指标注册表，metrics.enabled 为 false 时为 nil（nil 表示不记录）
+	Limits   ratelimit.Store      // 限流存储，ratelimit.enabled 为 false 时为 nil
+	Cache    *cache.Store         // 文章缓存，cache.enabled 为 false 时为 nil（nil 的 Store 表示不缓存）
+	Tracing  trace.TracerProvider // 链路追踪，由 EnableTracing 设置，未启用时为 nil
+
+	redis map[string]*redis.Client // 按连接地址共享的 Redis 客户端
+}
+
+// New 按配置创建各组件；返回错误时已创建的资源会被释放
+func New(cfg *config.Config) (*App, error) {
+	log, level := logger.New(logger.Options{
+		Level:              cfg.Log.Level,
+		Format:             cfg.Log.Format,
+		Output:             cfg.Log.Output,
+		File:               cfg.Log.File,
+		MaxSizeMB:          cfg.Log.MaxSizeMB,
+		MaxAgeDays:         cfg.Log.MaxAgeDays,
+		MaxBackups:         cfg.Log.MaxBackups,
+		Compress:           cfg.Log.Compress,
+		SamplingInitial:    cfg.Log.SamplingInitial,
+		SamplingThereafter: cfg.Log.SamplingThereafter,
+	})
+
+	tokens, err := auth.NewTokenIssuer(cfg.JWT.Secret, cfg.JWT.Lifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	dbLogger, err := database.NewLogger(log, cfg.DB.LogLevel, cfg.DB.SlowThreshold)
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.Open(database.Options{
+		Driver:          cfg.DB.Driver,
+		DSN:             cfg.DB.DSN,
+		MaxIdleConns:    cfg.DB.MaxIdleConn,
+		MaxOpenConns:    cfg.DB.MaxOpenConn,
+		ConnMaxLifetime: cfg.DB.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DB.ConnMaxIdleTime,
+		Logger:          dbLogger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("database initialization failed (driver %s): %w", cfg.DB.Driver, err)
+	}
+
+	a := &App{Config: cfg, Log: log, LogLevel: level, DB: db, Tokens: tokens, redis: make(map[string]*redis.Client)}
+	if cfg.Metrics.Enabled {
+		if err := a.enableMetrics(); err != nil {
+			_ = a.Close()
+			return nil, err
+		}
+	}
+	if err := a.openStores(); err != nil {
+		_ = a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// enableMetrics 创建本实例的指标，并记录 GORM 语句耗时与连接池状态
+func (a *App) enableMetrics() error {
+	a.Metrics = metrics.New()
+	if err := a.DB.Use(metrics.GormPlugin{Metrics: a.Metrics}); err != nil {
+		return fmt.Errorf("register metrics plugin: %w", err)
+	}
+	sqlDB, err := a.DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := a.Metrics.RegisterDBStats(sqlDB); err != nil {
+		return fmt.Errorf("register database metrics: %w", err)
+	}
+	return nil
+}
+
+// openStores 按配置创建限流存储与缓存；Redis 连接在首次使用时建立
+func (a *App) openStores() error {
+	cfg := a.Config
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Store {
+		case "redis":
+			client, err := a.redisClient(cfg.RateLimit.RedisURL)
+			if err != nil {
+				return fmt.Errorf("ratelimit.redis_url: %w", err)
+			}
+			a.Limits = ratelimit.NewRedisStore(client, "blog:ratelimit:")
+		default:
+			a.Limits = ratelimit.NewMemoryStore()
+		}
+	}
+
+	if cfg.Cache.Enabled {
+		var c cache.Cache
+		switch cfg.Cache.Store {
+		case "redis":
+			client, err := a.redisClient(cfg.Cache.RedisURL)
+			if err != nil {
+				return fmt.Errorf("cache.redis_url: %w", err)
+			}
+			c = cache.NewRedis(client, "blog:cache:")
+		default:
+			c = cache.NewMemory(cfg.Cache.MaxEntries)
+		}
+		a.Cache = cache.NewStore(c, cfg.Cache.TTL, a.Metrics)
+	}
+	return nil
+}
+
+// EnableTracing 使用 tp 追踪 HTTP 请求（NewRouter 读取 a.Tracing），并为 GORM 语句创建子 span；
+// 需在 NewRouter 之前调用，tp 的 Shutdown 由调用方负责
+func (a *App) EnableTracing(tp trace.TracerProvider) error {
+	if err := a.DB.Use(tracing.GormPlugin{}); err != nil {
+		return fmt.Errorf("register tracing plugin: %w", err)
+	}
+	a.Tracing = tp
+	return nil
+}
+
+// redisClient 返回连接到 url 的客户端，限流与缓存使用同一地址时共享连接池
+func (a *App) redisClient(url string) (*redis.Client, error) {
+	if client, ok := a.redis[url]; ok {
+		return client, nil
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	a.redis[url] = client
+	return client, nil
+}
+
+// Close 关闭数据库与 Redis 连接并刷新日志；重复调用无副作用
+func (a *App) Close() error {
+	err := database.Close(a.DB)
+	for _, client := range a.redis {
+		if closeErr := client.Close(); closeErr != nil && !errors.Is(closeErr, redis.ErrClosed) {
+			err = errors.Join(err, closeErr)
+		}
+	}
+	_ = a.Log.Sync() // 输出到终端时 Sync 会返回 EINVAL 等无意义的错误
+	return err
+}
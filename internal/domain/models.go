@@ -1,13 +1,36 @@
 package domain
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ValidRole 判断角色名是否合法
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
 
 type User struct {
 	gorm.Model
-	Username string `gorm:"size:50;uniqueIndex;not null"`
-	Password string `gorm:"size:100;not null"`
-	Email    string `gorm:"size:100;uniqueIndex;not null"`
-	Posts    []Post `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Username     string     `gorm:"size:50;uniqueIndex;not null"`
+	Password     string     `gorm:"size:100;not null"`
+	Email        string     `gorm:"size:100;uniqueIndex;not null"`
+	Role         string     `gorm:"size:20;not null;default:user"`
+	DisabledAt   *time.Time // 非空表示账号已被禁用
+	TokenVersion uint       `gorm:"not null;default:0"` // 签入令牌的版本号，重置密码或禁用账号时递增，使已签发的令牌失效
+	Posts        []Post     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// Disabled 账号是否已被禁用
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
 }
 
 type Post struct {
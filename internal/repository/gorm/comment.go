@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"context"
+
+	gormio "gorm.io/gorm"
+)
+
+var _ repository.CommentRepository = (*CommentRepository)(nil)
+
+type CommentRepository struct {
+	db *gormio.DB
+}
+
+func NewCommentRepository(db *gormio.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *CommentRepository) GetByID(ctx context.Context, id uint) (*domain.Comment, error) {
+	var comment domain.Comment
+	if err := r.db.WithContext(ctx).First(&comment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &comment, nil
+}
+
+func (r *CommentRepository) ListByPost(ctx context.Context, postID uint, page repository.Page) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	err := keyset(r.db.WithContext(ctx).Preload("User").Where("post_id = ?", postID), page, false).
+		Find(&comments).Error
+	return inDisplayOrder(comments, page), err
+}
+
+func (r *CommentRepository) CountByPost(ctx context.Context, postID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Comment{}).Where("post_id = ?", postID).Count(&count).Error
+	return count, err
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, comment *domain.Comment) error {
+	return r.db.WithContext(ctx).Delete(comment).Error
+}
@@ -0,0 +1,48 @@
+// Package gorm 基于 GORM 的仓储实现
+package gorm
+
+import (
+	"blogSystem/internal/repository"
+	"errors"
+	"slices"
+
+	gormio "gorm.io/gorm"
+)
+
+// translateError 把 GORM 的"未找到"错误转换为 repository.ErrNotFound
+func translateError(err error) error {
+	if errors.Is(err, gormio.ErrRecordNotFound) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// keyset 按 (created_at, id) 键集分页，desc 表示展示顺序为倒序。
+// 取上一页（Before）时按相反方向查询，取出后需由 inDisplayOrder 恢复展示顺序；
+// 条件展开为 OR 形式而不用行值比较，各数据库都能使用 (created_at, id) 上的索引
+func keyset(db *gormio.DB, page repository.Page, desc bool) *gormio.DB {
+	cursor, forward := page.After, true
+	if page.Before != nil {
+		cursor, forward = page.Before, false
+	}
+	op, order := ">", "ASC"
+	if desc == forward {
+		op, order = "<", "DESC"
+	}
+	if cursor != nil {
+		db = db.Where("(created_at "+op+" ? OR (created_at = ? AND id "+op+" ?))",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+	if forward && page.Offset > 0 {
+		db = db.Offset(page.Offset)
+	}
+	return db.Order("created_at " + order).Order("id " + order).Limit(page.Limit)
+}
+
+// inDisplayOrder 把 keyset 取上一页时反向查询的结果恢复为展示顺序
+func inDisplayOrder[T any](items []T, page repository.Page) []T {
+	if page.Before != nil {
+		slices.Reverse(items)
+	}
+	return items
+}
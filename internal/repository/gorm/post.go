@@ -0,0 +1,131 @@
+package gorm
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/pkg/database"
+	"context"
+	"errors"
+	"time"
+
+	gormio "gorm.io/gorm"
+)
+
+var _ repository.PostRepository = (*PostRepository)(nil)
+
+type PostRepository struct {
+	db *gormio.DB
+}
+
+func NewPostRepository(db *gormio.DB) *PostRepository {
+	return &PostRepository{db: db}
+}
+
+func (r *PostRepository) Create(ctx context.Context, post *domain.Post) error {
+	return r.db.WithContext(ctx).Create(post).Error
+}
+
+func (r *PostRepository) GetByID(ctx context.Context, id uint) (*domain.Post, error) {
+	var post domain.Post
+	if err := r.db.WithContext(ctx).First(&post, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &post, nil
+}
+
+func (r *PostRepository) GetDetail(ctx context.Context, id uint) (*domain.Post, error) {
+	var post domain.Post
+	err := r.db.WithContext(ctx).Preload("User").Preload("Comments.User").First(&post, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &post, nil
+}
+
+func (r *PostRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Post{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// Update 在事务中锁定文章行后更新，避免与并发的删除、更新交错
+func (r *PostRepository) Update(ctx context.Context, post *domain.Post, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gormio.DB) error {
+		var current domain.Post
+		if err := database.ForUpdate(tx).Select("id").First(&current, post.ID).Error; err != nil {
+			return translateError(err)
+		}
+		return tx.Model(post).Updates(updates).Error
+	})
+}
+
+func (r *PostRepository) Delete(ctx context.Context, post *domain.Post) error {
+	result := r.db.WithContext(ctx).Delete(post)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostRepository) List(ctx context.Context, page repository.Page) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := keyset(r.db.WithContext(ctx).Preload("User"), page, true).Find(&posts).Error
+	return inDisplayOrder(posts, page), err
+}
+
+func (r *PostRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Post{}).Count(&count).Error
+	return count, err
+}
+
+func (r *PostRepository) ListByUser(ctx context.Context, userID uint, page repository.Page) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := keyset(r.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User"), page, true).Find(&posts).Error
+	return inDisplayOrder(posts, page), err
+}
+
+func (r *PostRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Post{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// LastUpdated 取 updated_at 最大的一行而不用 MAX()，聚合结果在 SQLite 中是字符串，无法直接扫描为时间
+func (r *PostRepository) LastUpdated(ctx context.Context) (time.Time, error) {
+	var post domain.Post
+	err := r.db.WithContext(ctx).Select("updated_at").Order("updated_at DESC").Take(&post).Error
+	if errors.Is(err, gormio.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return post.UpdatedAt, err
+}
+
+func (r *PostRepository) Search(ctx context.Context, query string, page repository.Page) ([]domain.Post, error) {
+	var posts []domain.Post
+	err := keyset(database.MatchPosts(r.db.WithContext(ctx), query).Preload("User"), page, true).
+		Find(&posts).Error
+	return inDisplayOrder(posts, page), err
+}
+
+func (r *PostRepository) CountSearch(ctx context.Context, query string) (int64, error) {
+	var count int64
+	err := database.MatchPosts(r.db.WithContext(ctx).Model(&domain.Post{}), query).Count(&count).Error
+	return count, err
+}
+
+func (r *PostRepository) Scan(ctx context.Context, batchSize int, fn func(batch []domain.Post) error) error {
+	var batch []domain.Post
+	return r.db.WithContext(ctx).Model(&domain.Post{}).
+		Select("id", "user_id", "created_at", "updated_at").
+		FindInBatches(&batch, batchSize, func(tx *gormio.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+func (r *PostRepository) Reindex(ctx context.Context) error {
+	return database.ReindexPosts(r.db.WithContext(ctx))
+}
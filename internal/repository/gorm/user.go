@@ -0,0 +1,76 @@
+package gorm
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"context"
+
+	gormio "gorm.io/gorm"
+)
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	db *gormio.DB
+}
+
+func NewUserRepository(db *gormio.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(user).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) List(ctx context.Context, page repository.Page) ([]domain.User, error) {
+	var users []domain.User
+	err := keyset(r.db.WithContext(ctx), page, true).Find(&users).Error
+	return inDisplayOrder(users, page), err
+}
+
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Count(&count).Error
+	return count, err
+}
+
+func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	return r.exists(ctx, "username = ?", username)
+}
+
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return r.exists(ctx, "email = ?", email)
+}
+
+func (r *UserRepository) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where(query, args...).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"context"
+	"sort"
+)
+
+var _ repository.CommentRepository = (*CommentRepository)(nil)
+
+type CommentRepository struct {
+	store *Store
+}
+
+func (r *CommentRepository) Create(_ context.Context, comment *domain.Comment) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comment.ID = s.newID()
+	comment.CreatedAt, comment.UpdatedAt = now(), now()
+	stored := *comment
+	stored.User, stored.Post = domain.User{}, domain.Post{}
+	s.comments[comment.ID] = stored
+	return nil
+}
+
+func (r *CommentRepository) GetByID(_ context.Context, id uint) (*domain.Comment, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &comment, nil
+}
+
+func (r *CommentRepository) ListByPost(_ context.Context, postID uint, page repository.Page) ([]domain.Comment, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return paginate(s.commentsOf(postID), page, func(comment *domain.Comment) repository.Cursor {
+		return repository.Cursor{CreatedAt: comment.CreatedAt, ID: comment.ID}
+	}, false), nil
+}
+
+func (r *CommentRepository) CountByPost(_ context.Context, postID uint) (int64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, comment := range s.comments {
+		if comment.PostID == postID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *CommentRepository) Delete(_ context.Context, comment *domain.Comment) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.comments, comment.ID)
+	return nil
+}
+
+// commentsOf 返回文章下按发表时间正序（相同时按 ID 正序）排列的评论（含评论者），调用方需持有读锁
+func (s *Store) commentsOf(postID uint) []domain.Comment {
+	var comments []domain.Comment
+	for _, comment := range s.comments {
+		if comment.PostID == postID {
+			comment.User = s.user(comment.UserID)
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	return comments
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ repository.PostRepository = (*PostRepository)(nil)
+
+type PostRepository struct {
+	store *Store
+}
+
+func (r *PostRepository) Create(_ context.Context, post *domain.Post) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post.ID = s.newID()
+	post.CreatedAt, post.UpdatedAt = now(), now()
+	stored := *post
+	stored.User, stored.Comments = domain.User{}, nil
+	s.posts[post.ID] = stored
+	return nil
+}
+
+func (r *PostRepository) GetByID(_ context.Context, id uint) (*domain.Post, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &post, nil
+}
+
+func (r *PostRepository) GetDetail(_ context.Context, id uint) (*domain.Post, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	post.User = s.user(post.UserID)
+	post.Comments = s.commentsOf(id)
+	return &post, nil
+}
+
+func (r *PostRepository) Exists(_ context.Context, id uint) (bool, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.posts[id]
+	return ok, nil
+}
+
+func (r *PostRepository) Update(_ context.Context, post *domain.Post, updates map[string]interface{}) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.posts[post.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	applyUpdates(&stored, updates)
+	stored.UpdatedAt = now()
+	s.posts[post.ID] = stored
+
+	applyUpdates(post, updates)
+	post.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (r *PostRepository) Delete(_ context.Context, post *domain.Post) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.posts[post.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(s.posts, post.ID)
+	return nil
+}
+
+func (r *PostRepository) List(_ context.Context, page repository.Page) ([]domain.Post, error) {
+	return r.page(r.sorted(), page), nil
+}
+
+func (r *PostRepository) Count(_ context.Context) (int64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.posts)), nil
+}
+
+func (r *PostRepository) ListByUser(_ context.Context, userID uint, page repository.Page) ([]domain.Post, error) {
+	return r.page(r.byUser(userID), page), nil
+}
+
+func (r *PostRepository) CountByUser(_ context.Context, userID uint) (int64, error) {
+	return int64(len(r.byUser(userID))), nil
+}
+
+// byUser 返回某个用户的文章，顺序同 sorted
+func (r *PostRepository) byUser(userID uint) []domain.Post {
+	var posts []domain.Post
+	for _, post := range r.sorted() {
+		if post.UserID == userID {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+func (r *PostRepository) LastUpdated(context.Context) (time.Time, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, post := range s.posts {
+		if post.UpdatedAt.After(latest) {
+			latest = post.UpdatedAt
+		}
+	}
+	return latest, nil
+}
+
+// Search 以子串匹配模拟全文检索（与 SQLite 的行为一致）
+func (r *PostRepository) Search(_ context.Context, query string, page repository.Page) ([]domain.Post, error) {
+	return r.page(r.matching(query), page), nil
+}
+
+func (r *PostRepository) CountSearch(_ context.Context, query string) (int64, error) {
+	return int64(len(r.matching(query))), nil
+}
+
+// matching 返回标题或正文包含 query 的文章，顺序同 sorted
+func (r *PostRepository) matching(query string) []domain.Post {
+	var matched []domain.Post
+	for _, post := range r.sorted() {
+		if strings.Contains(post.Title, query) || strings.Contains(post.Content, query) {
+			matched = append(matched, post)
+		}
+	}
+	return matched
+}
+
+// page 截取分页并加载作者
+func (r *PostRepository) page(posts []domain.Post, page repository.Page) []domain.Post {
+	posts = paginate(posts, page, postCursor, true)
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := range posts {
+		posts[i].User = s.user(posts[i].UserID)
+	}
+	return posts
+}
+
+func postCursor(post *domain.Post) repository.Cursor {
+	return repository.Cursor{CreatedAt: post.CreatedAt, ID: post.ID}
+}
+
+func (r *PostRepository) Scan(_ context.Context, batchSize int, fn func(batch []domain.Post) error) error {
+	posts := r.sorted()
+	for start := 0; start < len(posts); start += batchSize {
+		if err := fn(posts[start:min(start+batchSize, len(posts))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sorted 返回按创建时间倒序（相同时按 ID 倒序）排列的文章副本
+func (r *PostRepository) sorted() []domain.Post {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	posts := make([]domain.Post, 0, len(s.posts))
+	for _, post := range s.posts {
+		posts = append(posts, post)
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+			return posts[i].CreatedAt.After(posts[j].CreatedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+	return posts
+}
+
+// Reindex 内存实现没有索引，直接返回
+func (r *PostRepository) Reindex(context.Context) error {
+	return nil
+}
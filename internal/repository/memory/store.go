@@ -0,0 +1,105 @@
+// Package memory 仓储接口的内存实现，用于在没有数据库的情况下测试业务逻辑
+package memory
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Store 三类仓储共享的内存数据，保证关联查询（作者、评论者）能互相看到
+type Store struct {
+	mu       sync.RWMutex
+	nextID   uint
+	users    map[uint]domain.User
+	posts    map[uint]domain.Post
+	comments map[uint]domain.Comment
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:    make(map[uint]domain.User),
+		posts:    make(map[uint]domain.Post),
+		comments: make(map[uint]domain.Comment),
+	}
+}
+
+func (s *Store) Users() *UserRepository {
+	return &UserRepository{store: s}
+}
+
+func (s *Store) Posts() *PostRepository {
+	return &PostRepository{store: s}
+}
+
+func (s *Store) Comments() *CommentRepository {
+	return &CommentRepository{store: s}
+}
+
+// newID 分配自增 ID，调用方需持有写锁
+func (s *Store) newID() uint {
+	s.nextID++
+	return s.nextID
+}
+
+// user 返回预加载用的用户副本，调用方需持有读锁
+func (s *Store) user(id uint) domain.User {
+	return s.users[id]
+}
+
+// paginate 在已按展示顺序排好的记录中按键集分页，与 GORM 实现的语义一致；
+// key 返回记录的排序键，desc 表示展示顺序为按 (created_at, id) 倒序
+func paginate[T any](items []T, page repository.Page, key func(*T) repository.Cursor, desc bool) []T {
+	// precedes 判断 a 在展示顺序中是否排在 b 之前
+	precedes := func(a, b repository.Cursor) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt) == desc
+		}
+		return a.ID != b.ID && (a.ID > b.ID) == desc
+	}
+
+	if page.Before != nil {
+		end := sort.Search(len(items), func(i int) bool { return !precedes(key(&items[i]), *page.Before) })
+		return items[max(0, end-page.Limit):end]
+	}
+	start := 0
+	if page.After != nil {
+		start = sort.Search(len(items), func(i int) bool { return precedes(*page.After, key(&items[i])) })
+	}
+	start = min(start+page.Offset, len(items))
+	return items[start:min(start+page.Limit, len(items))]
+}
+
+// applyUpdates 按列名（snake_case）把 updates 写入结构体对应字段
+func applyUpdates(dest any, updates map[string]interface{}) {
+	v := reflect.ValueOf(dest).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if value, ok := updates[snakeCase(t.Field(i).Name)]; ok {
+			v.Field(i).Set(reflect.ValueOf(value).Convert(t.Field(i).Type))
+		}
+	}
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"context"
+	"errors"
+	"sort"
+)
+
+// ErrDuplicate 违反唯一约束（模拟数据库唯一索引）
+var ErrDuplicate = errors.New("duplicate key")
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	store *Store
+}
+
+func (r *UserRepository) Create(_ context.Context, user *domain.User) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Username == user.Username || u.Email == user.Email {
+			return ErrDuplicate
+		}
+	}
+	user.ID = s.newID()
+	user.CreatedAt, user.UpdatedAt = now(), now()
+	s.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) GetByID(_ context.Context, id uint) (*domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) GetByUsername(_ context.Context, username string) (*domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *UserRepository) Update(_ context.Context, user *domain.User, updates map[string]interface{}) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.users[user.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	applyUpdates(&stored, updates)
+	stored.UpdatedAt = now()
+	s.users[user.ID] = stored
+
+	applyUpdates(user, updates)
+	user.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// List 按注册时间倒序（相同时按 ID 倒序）分页
+func (r *UserRepository) List(_ context.Context, page repository.Page) ([]domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.After(users[j].CreatedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+	return paginate(users, page, func(user *domain.User) repository.Cursor {
+		return repository.Cursor{CreatedAt: user.CreatedAt, ID: user.ID}
+	}, true), nil
+}
+
+func (r *UserRepository) Count(_ context.Context) (int64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.users)), nil
+}
+
+func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	_, err := r.GetByUsername(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *UserRepository) ExistsByEmail(_ context.Context, email string) (bool, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"blogSystem/internal/domain"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound 记录不存在；各实现需把底层的"未找到"错误统一转换为它
+var ErrNotFound = errors.New("record not found")
+
+// Cursor 键集分页的位置，即一条记录的排序键 (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// Page 键集分页参数：取排在 After 之后（下一页）或 Before 之前（上一页）的 Limit 条记录，
+// 两者都为空时从第一条开始。结果总是按展示顺序返回。
+// Offset 只为兼容按页码翻页的旧参数保留，仅在不带 Before 时生效
+type Page struct {
+	After  *Cursor
+	Before *Cursor
+	Offset int
+	Limit  int
+}
+
+// UserRepository 用户数据访问
+type UserRepository interface {
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id uint) (*domain.User, error)
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	// Update 按字段更新用户，成功后 user 中对应字段与 UpdatedAt 同步为新值
+	Update(ctx context.Context, user *domain.User, updates map[string]interface{}) error
+	// List 按注册时间倒序分页查询
+	List(ctx context.Context, page Page) ([]domain.User, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+// PostRepository 文章数据访问
+type PostRepository interface {
+	Create(ctx context.Context, post *domain.Post) error
+	// GetByID 只查询文章本身，不加载关联数据
+	GetByID(ctx context.Context, id uint) (*domain.Post, error)
+	// GetDetail 查询文章并加载作者、评论及评论者
+	GetDetail(ctx context.Context, id uint) (*domain.Post, error)
+	Exists(ctx context.Context, id uint) (bool, error)
+	// Update 按字段更新文章，成功后 post 中对应字段与 UpdatedAt 同步为新值；
+	// 文章在此期间已被删除时返回 ErrNotFound
+	Update(ctx context.Context, post *domain.Post, updates map[string]interface{}) error
+	Delete(ctx context.Context, post *domain.Post) error
+	// List 按创建时间倒序分页查询，并加载作者
+	List(ctx context.Context, page Page) ([]domain.Post, error)
+	Count(ctx context.Context) (int64, error)
+	// ListByUser 按创建时间倒序分页查询某个用户的文章，并加载作者
+	ListByUser(ctx context.Context, userID uint, page Page) ([]domain.Post, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	// LastUpdated 全部文章中最新的更新时间，没有文章时为零值
+	LastUpdated(ctx context.Context) (time.Time, error)
+	// Search 按标题和正文全文检索，结果按创建时间倒序分页并加载作者
+	Search(ctx context.Context, query string, page Page) ([]domain.Post, error)
+	// CountSearch 全文检索命中的文章数
+	CountSearch(ctx context.Context, query string) (int64, error)
+	// Scan 分批遍历全部文章（仅 ID、UserID、UpdatedAt 等基础字段），用于建立索引
+	Scan(ctx context.Context, batchSize int, fn func(batch []domain.Post) error) error
+	// Reindex 重建全文检索索引
+	Reindex(ctx context.Context) error
+}
+
+// CommentRepository 评论数据访问
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.Comment) error
+	GetByID(ctx context.Context, id uint) (*domain.Comment, error)
+	// ListByPost 按发表时间正序分页查询文章下的评论，并加载评论者
+	ListByPost(ctx context.Context, postID uint, page Page) ([]domain.Comment, error)
+	CountByPost(ctx context.Context, postID uint) (int64, error)
+	Delete(ctx context.Context, comment *domain.Comment) error
+}
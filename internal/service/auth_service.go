@@ -2,48 +2,113 @@ package service
 
 import (
 	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
 	"blogSystem/pkg/auth"
+	"blogSystem/pkg/metrics"
+	"blogSystem/pkg/tracing"
+	"context"
 	"errors"
+	"sync/atomic"
 
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
 type AuthService struct {
-	db *gorm.DB
+	users              repository.UserRepository
+	tokens             *auth.TokenIssuer
+	metrics            *metrics.Metrics // 登录结果计数，nil 表示不记录
+	registrationClosed atomic.Bool
 }
 
-func NewAuthService(db *gorm.DB) *AuthService {
-	return &AuthService{db: db}
+func NewAuthService(users repository.UserRepository, tokens *auth.TokenIssuer) *AuthService {
+	return &AuthService{users: users, tokens: tokens}
 }
 
-func (s *AuthService) Register(user *domain.User) error {
-	// 检查用户名是否已存在
-	var count int64
-	s.db.Model(&domain.User{}).Where("username = ?", user.Username).Count(&count)
-	if count > 0 {
-		return errors.New("username already exists")
+// SetRegistrationOpen 开放或关闭自助注册，可在运行时随配置热更新
+func (s *AuthService) SetRegistrationOpen(open bool) {
+	s.registrationClosed.Store(!open)
+}
+
+// SetMetrics 记录登录成功与失败次数；需在处理请求前调用
+func (s *AuthService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+func (s *AuthService) Register(ctx context.Context, user *domain.User) error {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
+	if s.registrationClosed.Load() {
+		return ErrRegistrationClosed
+	}
+
+	// 检查用户名、邮箱是否已存在
+	exists, err := s.users.ExistsByUsername(ctx, user.Username)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUsernameTaken
+	}
+	if exists, err = s.users.ExistsByEmail(ctx, user.Email); err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailTaken
+	}
+
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+	if !domain.ValidRole(user.Role) {
+		return ErrInvalidRole
 	}
 
 	// 密码加密
-	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashed, err := hashPassword(user.Password)
 	if err != nil {
 		return err
 	}
-	user.Password = string(hashed)
+	user.Password = hashed
 
-	return s.db.Create(user).Error
+	return s.users.Create(ctx, user)
 }
 
-func (s *AuthService) Login(username, password string) (string, error) {
-	var user domain.User
-	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
-		return "", errors.New("invalid credentials")
+func (s *AuthService) Login(ctx context.Context, username, password string) (string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	user, err := s.users.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.metrics.Login(metrics.LoginFailure)
+			return "", ErrInvalidCredentials
+		}
+		return "", err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+		s.metrics.Login(metrics.LoginFailure)
+		return "", ErrInvalidCredentials
+	}
+	// 密码校验通过后再判断禁用状态，避免借此探测账号是否存在
+	if user.Disabled() {
+		s.metrics.Login(metrics.LoginFailure)
+		return "", ErrUserDisabled
 	}
 
-	return auth.GenerateToken(user.ID)
+	token, err := s.tokens.Issue(auth.Claims{UserID: user.ID, Version: user.TokenVersion})
+	if err != nil {
+		return "", err
+	}
+	s.metrics.Login(metrics.LoginSuccess)
+	return token, nil
+}
+
+func hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
 }
@@ -2,36 +2,98 @@ package service
 
 import (
 	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/pkg/cache"
+	"blogSystem/pkg/metrics"
+	"blogSystem/pkg/tracing"
+	"context"
 	"errors"
-
-	"gorm.io/gorm"
 )
 
 type CommentService struct {
-	db *gorm.DB
+	comments repository.CommentRepository
+	posts    repository.PostRepository
+	cache    *cache.Store     // 与 PostService 共用，评论变化时删除所属文章的详情缓存
+	metrics  *metrics.Metrics // 业务事件计数，nil 表示不记录
+}
+
+func NewCommentService(comments repository.CommentRepository, posts repository.PostRepository) *CommentService {
+	return &CommentService{comments: comments, posts: posts}
+}
+
+// SetCache 与 PostService.SetCache 使用同一个 Store；需在处理请求前调用
+func (s *CommentService) SetCache(c *cache.Store) {
+	s.cache = c
 }
 
-func NewCommentService(db *gorm.DB) *CommentService {
-	return &CommentService{db: db}
+// SetMetrics 记录新建评论数；需在处理请求前调用
+func (s *CommentService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
 }
 
-func (s *CommentService) Create(comment *domain.Comment) error {
-	return s.db.Create(comment).Error
+func (s *CommentService) Create(ctx context.Context, comment *domain.Comment) error {
+	ctx, span := tracing.Start(ctx, "CommentService.Create")
+	defer span.End()
+
+	if err := s.ensurePostExists(ctx, comment.PostID); err != nil {
+		return err
+	}
+	if err := s.comments.Create(ctx, comment); err != nil {
+		return err
+	}
+	s.metrics.CommentCreated()
+	s.cache.Delete(ctx, postCacheKey(comment.PostID))
+	return nil
 }
 
-func (s *CommentService) GetByPostID(postID uint) ([]domain.Comment, error) {
-	var comments []domain.Comment
-	err := s.db.Debug().Preload("User").Where("post_id = ?", postID).Find(&comments).Error
-	return comments, err
+// GetByPostID 按发表时间正序分页查询文章下的评论
+func (s *CommentService) GetByPostID(ctx context.Context, postID uint, req PageRequest) (*Page[domain.Comment], error) {
+	ctx, span := tracing.Start(ctx, "CommentService.GetByPostID")
+	defer span.End()
+
+	if err := s.ensurePostExists(ctx, postID); err != nil {
+		return nil, err
+	}
+	return paginate(ctx, req,
+		func(comment *domain.Comment) repository.Cursor {
+			return repository.Cursor{CreatedAt: comment.CreatedAt, ID: comment.ID}
+		},
+		func(ctx context.Context, page repository.Page) ([]domain.Comment, error) {
+			return s.comments.ListByPost(ctx, postID, page)
+		},
+		func(ctx context.Context) (int64, error) {
+			return s.comments.CountByPost(ctx, postID)
+		})
+}
+
+func (s *CommentService) Delete(ctx context.Context, commentID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "CommentService.Delete")
+	defer span.End()
+
+	comment, err := s.comments.GetByID(ctx, commentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return ErrCommentForbidden
+	}
+	if err := s.comments.Delete(ctx, comment); err != nil {
+		return err
+	}
+	s.cache.Delete(ctx, postCacheKey(comment.PostID))
+	return nil
 }
 
-func (s *CommentService) Delete(commentID, userID uint) error {
-	result := s.db.Debug().Where("id = ? AND user_id = ?", commentID, userID).Delete(&domain.Comment{})
-	if result.Error != nil {
-		return result.Error
+func (s *CommentService) ensurePostExists(ctx context.Context, postID uint) error {
+	exists, err := s.posts.Exists(ctx, postID)
+	if err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("comment not found or not owned by user")
+	if !exists {
+		return ErrPostNotFound
 	}
 	return nil
 }
@@ -0,0 +1,78 @@
+package service
+
+// ErrorKind 业务错误分类，API 层据此决定 HTTP 状态码
+type ErrorKind int
+
+const (
+	KindValidation   ErrorKind = iota + 1 // 请求参数不合法
+	KindUnauthorized                      // 未认证或凭据错误
+	KindForbidden                         // 已认证但无权操作
+	KindNotFound                          // 资源不存在
+	KindConflict                          // 与现有数据冲突（如用户名重复）
+)
+
+// Error 带分类和稳定错误码的业务错误
+//
+//	Code 是给客户端程序判断用的稳定标识（如 post_not_found），不要随意修改；
+//	Message 是给人看的英文描述；Err 保存底层原因（可选），不会返回给客户端。
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewValidationError(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// 预定义的业务错误，可直接用 errors.Is 比较
+var (
+	ErrUsernameTaken      = NewConflictError("username_taken", "username already exists")
+	ErrEmailTaken         = NewConflictError("email_taken", "email already registered")
+	ErrInvalidCredentials = NewUnauthorizedError("invalid_credentials", "invalid credentials")
+	ErrUserNotFound       = NewNotFoundError("user_not_found", "user not found")
+	ErrUserDisabled       = NewForbiddenError("user_disabled", "user is disabled")
+	ErrInvalidRole        = NewValidationError("invalid_role", "role must be user or admin")
+	ErrPasswordTooShort   = NewValidationError("password_too_short", "password must be at least 3 characters")
+	ErrAdminRequired      = NewForbiddenError("admin_required", "administrator role required")
+	ErrRegistrationClosed = NewForbiddenError("registration_closed", "registration is closed")
+
+	ErrPostNotFound        = NewNotFoundError("post_not_found", "post not found")
+	ErrPostForbidden       = NewForbiddenError("post_forbidden", "post is not owned by user")
+	ErrNothingToUpdate     = NewValidationError("nothing_to_update", "no fields to update")
+	ErrSearchQueryRequired = NewValidationError("search_query_required", "search query is required")
+	ErrCommentNotFound     = NewNotFoundError("comment_not_found", "comment not found")
+	ErrCommentForbidden    = NewForbiddenError("comment_forbidden", "comment is not owned by user")
+
+	ErrInvalidCursor = NewValidationError("invalid_cursor", "invalid pagination cursor")
+
+	ErrSitemapPageNotFound = NewNotFoundError("sitemap_page_not_found", "sitemap page not found")
+)
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"blogSystem/internal/repository"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PageRequest 列表查询参数。Cursor 为上一次响应返回的不透明游标，为空时从第一条开始；
+// Page 为兼容旧客户端保留的页码（按 OFFSET 翻页，越靠后越慢），只在不带 Cursor 时使用；
+// WithTotal 为 false 时跳过总条数统计，适合只需要逐页加载的客户端
+type PageRequest struct {
+	Cursor    string
+	Page      int
+	Size      int
+	WithTotal bool
+}
+
+// Page 一页查询结果。Next/Prev 为下一页、上一页的游标，没有相邻页时为空；
+// Total 为满足条件的总条数，未要求统计时为 nil
+type Page[T any] struct {
+	Items []T
+	Next  string
+	Prev  string
+	Total *int64
+}
+
+// key 请求参数的缓存键片段
+func (r PageRequest) key() string {
+	return r.Cursor + ":" + strconv.Itoa(r.Page) + ":" + strconv.Itoa(r.Size) + ":" + strconv.FormatBool(r.WithTotal)
+}
+
+// paginate 按请求取一页：多取一条判断另一侧是否还有数据，并按需统计总条数。
+// cursor 返回记录的排序键，list/count 为对应的仓储查询
+func paginate[T any](ctx context.Context, req PageRequest, cursor func(*T) repository.Cursor,
+	list func(ctx context.Context, page repository.Page) ([]T, error),
+	count func(ctx context.Context) (int64, error)) (*Page[T], error) {
+	page, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	page.Limit = req.Size + 1
+	if req.Cursor == "" && req.Page > 1 {
+		page.Offset = (req.Page - 1) * req.Size
+	}
+
+	items, err := list(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	// 多出的一条位于远离游标的一端：向后翻在末尾，向前翻在开头
+	more := len(items) > req.Size
+	if more && page.Before != nil {
+		items = items[1:]
+	} else if more {
+		items = items[:req.Size]
+	}
+
+	result := &Page[T]{Items: items}
+	if len(items) > 0 {
+		backward := page.Before != nil
+		if more || backward {
+			result.Next = encodeCursor(cursorAfter, cursor(&items[len(items)-1]))
+		}
+		if (more && backward) || page.After != nil || page.Offset > 0 {
+			result.Prev = encodeCursor(cursorBefore, cursor(&items[0]))
+		}
+	}
+
+	if req.WithTotal {
+		total, err := count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+	return result, nil
+}
+
+// 游标方向：取排在该位置之后或之前的记录
+const (
+	cursorAfter  = "a"
+	cursorBefore = "b"
+)
+
+// encodeCursor 游标格式为 base64url("<方向>:<created_at 的 Unix 纳秒>:<id>")，
+// 对客户端不透明，只应原样传回
+func encodeCursor(direction string, c repository.Cursor) string {
+	raw := fmt.Sprintf("%s:%d:%d", direction, c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor 解析游标为分页位置；空游标表示从第一条开始，格式错误时返回 ErrInvalidCursor
+func decodeCursor(token string) (repository.Page, error) {
+	if token == "" {
+		return repository.Page{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return repository.Page{}, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return repository.Page{}, ErrInvalidCursor
+	}
+	nanos, err1 := strconv.ParseInt(parts[1], 10, 64)
+	id, err2 := strconv.ParseUint(parts[2], 10, 64)
+	if err1 != nil || err2 != nil || id == 0 {
+		return repository.Page{}, ErrInvalidCursor
+	}
+	// 还原为本地时区的时间：SQLite 按字符串比较时间，格式需与写入时（time.Now）一致
+	c := &repository.Cursor{CreatedAt: time.Unix(0, nanos), ID: uint(id)}
+	switch parts[0] {
+	case cursorAfter:
+		return repository.Page{After: c}, nil
+	case cursorBefore:
+		return repository.Page{Before: c}, nil
+	}
+	return repository.Page{}, ErrInvalidCursor
+}
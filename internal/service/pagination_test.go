@@ -0,0 +1,211 @@
+package service_test
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/service"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestPagination 游标翻页（前后两个方向）、兼容旧客户端的页码与总条数统计
+func TestPagination(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"PostPagination", testPostPagination},
+		{"PostPageNumbers", testPostPageNumbers},
+		{"SearchPagination", testSearchPagination},
+		{"CommentPagination", testCommentPagination},
+		{"UserPagination", testUserPagination},
+		{"InvalidCursor", testInvalidCursor},
+	})
+}
+
+// walk 从第一页开始沿 Next 翻到最后一页，再沿 Prev 翻回第一页，返回两个方向上依次看到的 ID
+func walk[T any](t *testing.T, size int, id func(*T) uint,
+	list func(req service.PageRequest) (*service.Page[T], error)) (forward, backward []uint) {
+	t.Helper()
+	req := service.PageRequest{Page: 1, Size: size}
+	var last *service.Page[T]
+	for {
+		page, err := list(req)
+		must(t, err)
+		if len(page.Items) > size {
+			t.Fatalf("page has %d items, size is %d", len(page.Items), size)
+		}
+		if req.Cursor == "" && page.Prev != "" {
+			t.Fatal("first page must not have a previous page")
+		}
+		for i := range page.Items {
+			forward = append(forward, id(&page.Items[i]))
+		}
+		last = page
+		if page.Next == "" {
+			break
+		}
+		req.Cursor = page.Next
+	}
+
+	for page := last; ; {
+		var ids []uint
+		for i := range page.Items {
+			ids = append(ids, id(&page.Items[i]))
+		}
+		backward = append(ids, backward...)
+		if page.Prev == "" {
+			break
+		}
+		next, err := list(service.PageRequest{Cursor: page.Prev, Size: size})
+		must(t, err)
+		if next.Next == "" {
+			t.Fatal("a page reached through Prev must link back with Next")
+		}
+		page = next
+	}
+	return forward, backward
+}
+
+func expectIDs(t *testing.T, what string, got, want []uint) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("%s: expected %v, got %v", what, want, got)
+	}
+}
+
+func postIDs(posts []domain.Post) []uint {
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func postID(post *domain.Post) uint { return post.ID }
+
+func testPostPagination(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	var want []uint
+	for i := 0; i < 7; i++ {
+		post := createPost(t, s, alice.ID, fmt.Sprintf("post %d", i))
+		want = append([]uint{post.ID}, want...) // 按创建时间倒序
+	}
+
+	forward, backward := walk(t, 3, postID, func(req service.PageRequest) (*service.Page[domain.Post], error) {
+		return s.posts.List(ctx, req)
+	})
+	expectIDs(t, "forward", forward, want)
+	expectIDs(t, "backward", backward, want)
+
+	page, err := s.posts.List(ctx, service.PageRequest{Page: 1, Size: 3, WithTotal: true})
+	must(t, err)
+	if page.Total == nil || *page.Total != 7 {
+		t.Fatalf("expected total 7, got %v", page.Total)
+	}
+	page, err = s.posts.List(ctx, service.PageRequest{Page: 1, Size: 3})
+	must(t, err)
+	if page.Total != nil {
+		t.Fatal("total must be skipped when not requested")
+	}
+}
+
+// testPostPageNumbers 兼容旧客户端的页码参数，结果与游标翻页一致，并提供游标形式的相邻页
+func testPostPageNumbers(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	var want []uint
+	for i := 0; i < 5; i++ {
+		post := createPost(t, s, alice.ID, fmt.Sprintf("post %d", i))
+		want = append([]uint{post.ID}, want...)
+	}
+
+	page, err := s.posts.List(ctx, service.PageRequest{Page: 2, Size: 2})
+	must(t, err)
+	expectIDs(t, "page 2", postIDs(page.Items), want[2:4])
+	if page.Next == "" || page.Prev == "" {
+		t.Fatal("page 2 must link to both neighbours")
+	}
+	prev, err := s.posts.List(ctx, service.PageRequest{Cursor: page.Prev, Size: 2})
+	must(t, err)
+	expectIDs(t, "previous page", postIDs(prev.Items), want[0:2])
+
+	page, err = s.posts.List(ctx, service.PageRequest{Page: 9, Size: 2})
+	must(t, err)
+	if len(page.Items) != 0 || page.Next != "" {
+		t.Fatalf("page beyond the end must be empty, got %v", postIDs(page.Items))
+	}
+}
+
+func testSearchPagination(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	var want []uint
+	for i := 0; i < 6; i++ {
+		title := fmt.Sprintf("golang tips %d", i)
+		if i%2 == 1 {
+			title = fmt.Sprintf("cooking notes %d", i)
+		}
+		post := createPost(t, s, alice.ID, title)
+		if i%2 == 0 {
+			want = append([]uint{post.ID}, want...)
+		}
+	}
+
+	forward, backward := walk(t, 2, postID, func(req service.PageRequest) (*service.Page[domain.Post], error) {
+		return s.posts.Search(ctx, "golang", req)
+	})
+	expectIDs(t, "forward", forward, want)
+	expectIDs(t, "backward", backward, want)
+
+	page, err := s.posts.Search(ctx, "golang", service.PageRequest{Page: 1, Size: 2, WithTotal: true})
+	must(t, err)
+	if *page.Total != 3 {
+		t.Fatalf("expected 3 matches, got %d", *page.Total)
+	}
+}
+
+func testCommentPagination(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	post, other := createPost(t, s, alice.ID, "hello"), createPost(t, s, alice.ID, "other")
+	var want []uint
+	for i := 0; i < 5; i++ {
+		want = append(want, createComment(t, s, alice.ID, post.ID, fmt.Sprintf("comment %d", i)).ID) // 按发表时间正序
+		createComment(t, s, alice.ID, other.ID, "elsewhere")
+	}
+
+	forward, backward := walk(t, 2, func(c *domain.Comment) uint { return c.ID },
+		func(req service.PageRequest) (*service.Page[domain.Comment], error) {
+			return s.comments.GetByPostID(ctx, post.ID, req)
+		})
+	expectIDs(t, "forward", forward, want)
+	expectIDs(t, "backward", backward, want)
+
+	page, err := s.comments.GetByPostID(ctx, post.ID, service.PageRequest{Page: 1, Size: 2, WithTotal: true})
+	must(t, err)
+	if *page.Total != 5 {
+		t.Fatalf("expected 5 comments, got %d", *page.Total)
+	}
+}
+
+func testUserPagination(t *testing.T, s *services) {
+	ctx := context.Background()
+	var want []uint
+	for i := 0; i < 5; i++ {
+		want = append([]uint{createUser(t, s, fmt.Sprintf("user%d", i)).ID}, want...)
+	}
+
+	forward, backward := walk(t, 2, func(u *domain.User) uint { return u.ID },
+		func(req service.PageRequest) (*service.Page[domain.User], error) {
+			return s.users.List(ctx, req)
+		})
+	expectIDs(t, "forward", forward, want)
+	expectIDs(t, "backward", backward, want)
+}
+
+func testInvalidCursor(t *testing.T, s *services) {
+	ctx := context.Background()
+	for _, cursor := range []string{"not base64!", "eDoxOjE", "YToxOjA", "YTp4OjE"} {
+		_, err := s.posts.List(ctx, service.PageRequest{Cursor: cursor, Size: 10})
+		expectError(t, err, service.ErrInvalidCursor)
+	}
+}
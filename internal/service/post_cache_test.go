@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"blogSystem/internal/service"
+	"context"
+	"testing"
+)
+
+// TestPostCache 文章详情与列表页启用了读穿透缓存（见 newServices），写操作后必须失效
+func TestPostCache(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"Invalidation", testPostCacheInvalidation},
+	})
+}
+
+// testPostCacheInvalidation 写操作之后不能读到缓存中的旧数据
+func testPostCacheInvalidation(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice, bob := createUser(t, s, "alice"), createUser(t, s, "bob")
+	post := createPost(t, s, alice.ID, "hello")
+	req := service.PageRequest{Page: 1, Size: 10, WithTotal: true}
+
+	if _, err := s.posts.GetByID(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	page, err := s.posts.List(ctx, req)
+	must(t, err)
+	if *page.Total != 1 {
+		t.Fatalf("expected 1 post, got %d", *page.Total)
+	}
+
+	must(t, s.posts.Update(ctx, post.ID, alice.ID, map[string]interface{}{"title": "edited"}))
+	createComment(t, s, bob.ID, post.ID, "first!")
+	createPost(t, s, bob.ID, "second")
+
+	got, err := s.posts.GetByID(ctx, post.ID)
+	must(t, err)
+	if got.Title != "edited" || len(got.Comments) != 1 {
+		t.Fatalf("stale post detail: title %q, %d comments", got.Title, len(got.Comments))
+	}
+	if got.User.Password != "" || got.Comments[0].User.Password != "" {
+		t.Fatal("cached post must not carry password hashes")
+	}
+	page, err = s.posts.List(ctx, req)
+	must(t, err)
+	if *page.Total != 2 || page.Items[1].Title != "edited" {
+		t.Fatalf("stale post list: total %d, %v", *page.Total, postIDs(page.Items))
+	}
+}
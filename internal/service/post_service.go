@@ -2,9 +2,14 @@ package service
 
 import (
 	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/pkg/cache"
+	"blogSystem/pkg/metrics"
+	"blogSystem/pkg/tracing"
+	"context"
 	"errors"
-
-	"gorm.io/gorm"
+	"strconv"
+	"strings"
 )
 
 // 4.文章管理功能
@@ -14,46 +19,211 @@ import (
 //	实现文章的更新功能，只有文章的作者才能更新自己的文章。
 //	实现文章的删除功能，只有文章的作者才能删除自己的文章。
 type PostService struct {
-	db *gorm.DB
+	posts     repository.PostRepository
+	observers []PostObserver
+	cache     *cache.Store     // 文章详情与列表页的缓存，nil 表示不缓存
+	metrics   *metrics.Metrics // 业务事件计数，nil 表示不记录
 }
 
-func NewPostService(db *gorm.DB) *PostService {
-	return &PostService{db: db}
+// PostObserver 在文章写入成功后接收通知（如站点地图的增量更新）
+type PostObserver interface {
+	PostSaved(post *domain.Post)
+	PostDeleted(postID uint)
 }
 
-func (s *PostService) Create(post *domain.Post) error {
-	return s.db.Create(post).Error
+func NewPostService(posts repository.PostRepository) *PostService {
+	return &PostService{posts: posts}
 }
 
-func (s *PostService) GetByID(id uint) (*domain.Post, error) {
-	var post domain.Post
-	err := s.db.Preload("User").Preload("Comments.User").First(&post, id).Error
-	return &post, err
+// AddObserver 注册文章变更观察者，需在处理请求前调用
+func (s *PostService) AddObserver(o PostObserver) {
+	s.observers = append(s.observers, o)
 }
 
-func (s *PostService) Update(postID, userID uint, updates map[string]interface{}) error {
-	return s.db.Debug().Model(&domain.Post{}).
-		Where("id = ? AND user_id = ?", postID, userID).
-		Updates(updates).Error
+func (s *PostService) Create(ctx context.Context, post *domain.Post) error {
+	ctx, span := tracing.Start(ctx, "PostService.Create")
+	defer span.End()
+
+	if err := s.posts.Create(ctx, post); err != nil {
+		return err
+	}
+	s.metrics.PostCreated()
+	s.cache.Bump(ctx, postListVersion)
+	s.notifySaved(post)
+	return nil
+}
+
+func (s *PostService) GetByID(ctx context.Context, id uint) (*domain.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetByID")
+	defer span.End()
+
+	post, err := cache.GetOrLoad(ctx, s.cache, "post", postCacheKey(id), func(ctx context.Context) (*domain.Post, error) {
+		post, err := s.posts.GetDetail(ctx, id)
+		if err == nil {
+			scrubPost(post)
+		}
+		return post, err
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	return post, err
 }
 
-func (s *PostService) Delete(postID, userID uint) error {
-	result := s.db.Where("id = ? AND user_id = ?", postID, userID).Delete(&domain.Post{})
-	if result.Error != nil {
-		return result.Error
+func (s *PostService) Update(ctx context.Context, postID, userID uint, updates map[string]interface{}) error {
+	ctx, span := tracing.Start(ctx, "PostService.Update")
+	defer span.End()
+
+	if len(updates) == 0 {
+		return ErrNothingToUpdate
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("post not found or not owned by user")
+
+	post, err := s.getOwned(ctx, postID, userID)
+	if err != nil {
+		return err
 	}
+
+	if err := s.posts.Update(ctx, post, updates); errors.Is(err, repository.ErrNotFound) {
+		return ErrPostNotFound
+	} else if err != nil {
+		return err
+	}
+	s.invalidate(ctx, postID)
+	s.notifySaved(post)
 	return nil
 }
 
-func (s *PostService) List(page, size int) ([]domain.Post, error) {
-	var posts []domain.Post
-	err := s.db.Preload("User").
-		Offset((page - 1) * size).
-		Limit(size).
-		Order("created_at DESC").
-		Find(&posts).Error
-	return posts, err
+func (s *PostService) Delete(ctx context.Context, postID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "PostService.Delete")
+	defer span.End()
+
+	post, err := s.getOwned(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.posts.Delete(ctx, post); errors.Is(err, repository.ErrNotFound) {
+		return ErrPostNotFound
+	} else if err != nil {
+		return err
+	}
+	s.invalidate(ctx, postID)
+	for _, o := range s.observers {
+		o.PostDeleted(postID)
+	}
+	return nil
+}
+
+// SetCache 启用文章详情与列表页的读穿透缓存，写操作成功后使对应缓存失效；需在处理请求前调用
+func (s *PostService) SetCache(c *cache.Store) {
+	s.cache = c
+}
+
+// SetMetrics 记录新建文章数；需在处理请求前调用
+func (s *PostService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// getOwned 查询文章并校验作者，区分"不存在"与"无权操作"
+func (s *PostService) getOwned(ctx context.Context, postID, userID uint) (*domain.Post, error) {
+	post, err := s.posts.GetByID(ctx, postID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, ErrPostForbidden
+	}
+	return post, nil
+}
+
+func (s *PostService) List(ctx context.Context, req PageRequest) (*Page[domain.Post], error) {
+	ctx, span := tracing.Start(ctx, "PostService.List")
+	defer span.End()
+
+	// 列表键包含版本号，任何文章变化后更换版本号，各页缓存一并失效
+	key := "posts:list:" + s.cache.Version(ctx, postListVersion) + ":" + req.key()
+	return cache.GetOrLoad(ctx, s.cache, "post_list", key, func(ctx context.Context) (*Page[domain.Post], error) {
+		page, err := paginate(ctx, req, postCursor, s.posts.List, s.posts.Count)
+		if err == nil {
+			for i := range page.Items {
+				scrubPost(&page.Items[i])
+			}
+		}
+		return page, err
+	})
+}
+
+// ListByAuthor 某个用户发表的文章，即 sitemap 中的作者页面；用户不存在或没有文章时返回空列表
+func (s *PostService) ListByAuthor(ctx context.Context, userID uint, req PageRequest) (*Page[domain.Post], error) {
+	ctx, span := tracing.Start(ctx, "PostService.ListByAuthor")
+	defer span.End()
+
+	return paginate(ctx, req, postCursor,
+		func(ctx context.Context, page repository.Page) ([]domain.Post, error) {
+			return s.posts.ListByUser(ctx, userID, page)
+		},
+		func(ctx context.Context) (int64, error) {
+			return s.posts.CountByUser(ctx, userID)
+		})
+}
+
+// Search 按关键词检索文章
+func (s *PostService) Search(ctx context.Context, query string, req PageRequest) (*Page[domain.Post], error) {
+	ctx, span := tracing.Start(ctx, "PostService.Search")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	return paginate(ctx, req, postCursor,
+		func(ctx context.Context, page repository.Page) ([]domain.Post, error) {
+			return s.posts.Search(ctx, query, page)
+		},
+		func(ctx context.Context) (int64, error) {
+			return s.posts.CountSearch(ctx, query)
+		})
+}
+
+// invalidate 文章修改或删除后使其详情与全部列表页的缓存失效
+func (s *PostService) invalidate(ctx context.Context, postID uint) {
+	s.cache.Delete(ctx, postCacheKey(postID))
+	s.cache.Bump(ctx, postListVersion)
+}
+
+func (s *PostService) notifySaved(post *domain.Post) {
+	for _, o := range s.observers {
+		o.PostSaved(post)
+	}
+}
+
+// Reindex 重建文章全文检索索引
+func (s *PostService) Reindex(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "PostService.Reindex")
+	defer span.End()
+
+	return s.posts.Reindex(ctx)
+}
+
+// postListVersion 文章列表页缓存的版本号名称
+const postListVersion = "posts:list"
+
+// postCacheKey 文章详情（含评论）的缓存键，评论变化时由 CommentService 删除
+func postCacheKey(id uint) string {
+	return "posts:detail:" + strconv.FormatUint(uint64(id), 10)
+}
+
+func postCursor(post *domain.Post) repository.Cursor {
+	return repository.Cursor{CreatedAt: post.CreatedAt, ID: post.ID}
+}
+
+// scrubPost 清除缓存中不需要的敏感字段（作者与评论者的密码哈希），缓存可能位于共享的 Redis 中
+func scrubPost(post *domain.Post) {
+	post.User.Password = ""
+	for i := range post.Comments {
+		post.Comments[i].User.Password = ""
+	}
 }
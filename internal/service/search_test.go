@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/service"
+	"context"
+	"testing"
+)
+
+// TestSearch 全文检索；SQLite 以 LIKE 匹配，内存实现以子串匹配，两者结果一致
+func TestSearch(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"RequiresQuery", testSearchRequiresQuery},
+		{"MatchesTitleAndContent", testSearchMatchesTitleAndContent},
+	})
+}
+
+func testSearchRequiresQuery(t *testing.T, s *services) {
+	for _, query := range []string{"", "  "} {
+		_, err := s.posts.Search(context.Background(), query, service.PageRequest{Page: 1, Size: 10})
+		expectError(t, err, service.ErrSearchQueryRequired)
+	}
+}
+
+// testSearchMatchesTitleAndContent 关键词中的 % 与 _ 按字面匹配，不作为通配符
+func testSearchMatchesTitleAndContent(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	var ids []uint
+	for _, post := range []*domain.Post{
+		{Title: "100% done", Content: "finished", UserID: alice.ID},
+		{Title: "notes", Content: "coverage is 100% now", UserID: alice.ID},
+		{Title: "1000 items", Content: "nothing to see", UserID: alice.ID},
+		{Title: "snake_case", Content: "naming", UserID: alice.ID},
+	} {
+		must(t, s.posts.Create(ctx, post))
+		ids = append(ids, post.ID)
+	}
+
+	cases := []struct {
+		query string
+		want  []uint
+	}{
+		{" 100% ", []uint{ids[1], ids[0]}},
+		{"snake_", []uint{ids[3]}},
+		{"e_c", []uint{ids[3]}},
+		{"missing", nil},
+	}
+	for _, tc := range cases {
+		page, err := s.posts.Search(ctx, tc.query, service.PageRequest{Page: 1, Size: 10, WithTotal: true})
+		must(t, err)
+		expectIDs(t, "search "+tc.query, postIDs(page.Items), tc.want)
+		if *page.Total != int64(len(tc.want)) {
+			t.Errorf("search %q: total %d, want %d", tc.query, *page.Total, len(tc.want))
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/internal/repository/memory"
+	"blogSystem/internal/service"
+	"blogSystem/pkg/auth"
+	"blogSystem/pkg/cache"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repos 一组互相关联的仓储实现；同一套用例分别跑在内存实现与 GORM（SQLite）实现上
+type repos struct {
+	users    repository.UserRepository
+	posts    repository.PostRepository
+	comments repository.CommentRepository
+}
+
+// services 基于 repos 组装的服务，文章与评论启用内存缓存以覆盖缓存失效
+type services struct {
+	repos
+	tokens   *auth.TokenIssuer
+	auth     *service.AuthService
+	users    *service.UserService
+	posts    *service.PostService
+	comments *service.CommentService
+}
+
+func newServices(t *testing.T, r repos) *services {
+	t.Helper()
+	tokens, err := auth.NewTokenIssuer(strings.Repeat("s", 32), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := cache.NewStore(cache.NewMemory(100), time.Minute, nil)
+	s := &services{
+		repos:    r,
+		tokens:   tokens,
+		auth:     service.NewAuthService(r.users, tokens),
+		users:    service.NewUserService(r.users),
+		posts:    service.NewPostService(r.posts),
+		comments: service.NewCommentService(r.comments, r.posts),
+	}
+	s.posts.SetCache(store)
+	s.comments.SetCache(store)
+	return s
+}
+
+// serviceCase 一个业务规则用例，在全新的仓储与服务上执行
+type serviceCase struct {
+	name string
+	fn   func(t *testing.T, s *services)
+}
+
+// runServiceCases 每个用例分别跑在内存仓储与 GORM（SQLite）仓储上；
+// 各功能的测试文件用它登记自己的用例
+func runServiceCases(t *testing.T, cases []serviceCase) {
+	backends := []struct {
+		name     string
+		newRepos func(t *testing.T) repos
+	}{
+		{"Memory", newMemoryRepos},
+		{"SQLite", newSQLiteRepos},
+	}
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			for _, c := range cases {
+				t.Run(c.name, func(t *testing.T) {
+					c.fn(t, newServices(t, b.newRepos(t)))
+				})
+			}
+		})
+	}
+}
+
+func newMemoryRepos(*testing.T) repos {
+	store := memory.NewStore()
+	return repos{users: store.Users(), posts: store.Posts(), comments: store.Comments()}
+}
+
+// TestServices 仓储之上的业务规则：注册与登录、所有权校验、不存在与冲突错误
+func TestServices(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"RegisterConflicts", testRegisterConflicts},
+		{"LoginRejectsBadCredentials", testLoginRejectsBadCredentials},
+		{"PostOwnership", testPostOwnership},
+		{"PostNotFound", testPostNotFound},
+		{"CommentOwnership", testCommentOwnership},
+		{"CommentNotFound", testCommentNotFound},
+	})
+}
+
+// expectError 断言 err 为预定义的业务错误 want
+func expectError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// createUser 直接写入仓储，跳过注册时的密码哈希
+func createUser(t *testing.T, s *services, username string) *domain.User {
+	t.Helper()
+	user := &domain.User{Username: username, Password: "x", Email: username + "@example.com", Role: domain.RoleUser}
+	must(t, s.repos.users.Create(context.Background(), user))
+	return user
+}
+
+func createPost(t *testing.T, s *services, userID uint, title string) *domain.Post {
+	t.Helper()
+	post := &domain.Post{Title: title, Content: "content of " + title, UserID: userID}
+	must(t, s.posts.Create(context.Background(), post))
+	return post
+}
+
+func createComment(t *testing.T, s *services, userID, postID uint, content string) *domain.Comment {
+	t.Helper()
+	comment := &domain.Comment{Content: content, UserID: userID, PostID: postID}
+	must(t, s.comments.Create(context.Background(), comment))
+	return comment
+}
+
+func testRegisterConflicts(t *testing.T, s *services) {
+	ctx := context.Background()
+	must(t, s.auth.Register(ctx, &domain.User{Username: "alice", Password: "secret", Email: "alice@example.com"}))
+
+	err := s.auth.Register(ctx, &domain.User{Username: "alice", Password: "secret", Email: "other@example.com"})
+	expectError(t, err, service.ErrUsernameTaken)
+	err = s.auth.Register(ctx, &domain.User{Username: "alice2", Password: "secret", Email: "alice@example.com"})
+	expectError(t, err, service.ErrEmailTaken)
+
+	s.auth.SetRegistrationOpen(false)
+	err = s.auth.Register(ctx, &domain.User{Username: "bob", Password: "secret", Email: "bob@example.com"})
+	expectError(t, err, service.ErrRegistrationClosed)
+}
+
+func testLoginRejectsBadCredentials(t *testing.T, s *services) {
+	ctx := context.Background()
+	must(t, s.auth.Register(ctx, &domain.User{Username: "alice", Password: "secret", Email: "alice@example.com"}))
+
+	if _, err := s.auth.Login(ctx, "alice", "secret"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	_, err := s.auth.Login(ctx, "alice", "wrong")
+	expectError(t, err, service.ErrInvalidCredentials)
+	_, err = s.auth.Login(ctx, "nobody", "secret")
+	expectError(t, err, service.ErrInvalidCredentials)
+
+}
+
+func testPostOwnership(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice, bob := createUser(t, s, "alice"), createUser(t, s, "bob")
+	post := createPost(t, s, alice.ID, "hello")
+
+	err := s.posts.Update(ctx, post.ID, bob.ID, map[string]interface{}{"title": "hijacked"})
+	expectError(t, err, service.ErrPostForbidden)
+	expectError(t, s.posts.Delete(ctx, post.ID, bob.ID), service.ErrPostForbidden)
+	expectError(t, s.posts.Update(ctx, post.ID, alice.ID, map[string]interface{}{}), service.ErrNothingToUpdate)
+
+	must(t, s.posts.Update(ctx, post.ID, alice.ID, map[string]interface{}{"title": "edited"}))
+	got, err := s.posts.GetByID(ctx, post.ID)
+	must(t, err)
+	if got.Title != "edited" || got.User.Username != "alice" {
+		t.Fatalf("unexpected post after update: title %q, author %q", got.Title, got.User.Username)
+	}
+
+	must(t, s.posts.Delete(ctx, post.ID, alice.ID))
+	_, err = s.posts.GetByID(ctx, post.ID)
+	expectError(t, err, service.ErrPostNotFound)
+}
+
+func testPostNotFound(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+
+	_, err := s.posts.GetByID(ctx, 999)
+	expectError(t, err, service.ErrPostNotFound)
+	expectError(t, s.posts.Update(ctx, 999, alice.ID, map[string]interface{}{"title": "x"}), service.ErrPostNotFound)
+	expectError(t, s.posts.Delete(ctx, 999, alice.ID), service.ErrPostNotFound)
+}
+
+func testCommentOwnership(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice, bob := createUser(t, s, "alice"), createUser(t, s, "bob")
+	post := createPost(t, s, alice.ID, "hello")
+	comment := createComment(t, s, bob.ID, post.ID, "nice post")
+
+	expectError(t, s.comments.Delete(ctx, comment.ID, alice.ID), service.ErrCommentForbidden)
+	must(t, s.comments.Delete(ctx, comment.ID, bob.ID))
+	expectError(t, s.comments.Delete(ctx, comment.ID, bob.ID), service.ErrCommentNotFound)
+}
+
+func testCommentNotFound(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+
+	err := s.comments.Create(ctx, &domain.Comment{Content: "orphan", UserID: alice.ID, PostID: 999})
+	expectError(t, err, service.ErrPostNotFound)
+	_, err = s.comments.GetByPostID(ctx, 999, service.PageRequest{Page: 1, Size: 10})
+	expectError(t, err, service.ErrPostNotFound)
+	expectError(t, s.comments.Delete(ctx, 999, alice.ID), service.ErrCommentNotFound)
+}
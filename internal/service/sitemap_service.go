@@ -0,0 +1,287 @@
+package service
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/pkg/tracing"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 单个 sitemap 文件最多 50000 条 URL（sitemaps.org 协议限制）
+const SitemapMaxURLs = 50000
+
+type sitemapPost struct {
+	authorID  uint
+	updatedAt time.Time
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// SitemapService 维护站点地图的内存索引
+//
+//	首次访问时全表扫描一次文章表建立索引，之后通过 PostObserver 增量更新，
+//	只有索引发生变化时才重新渲染 XML。
+//	PostObserver 只能收到本进程内的写操作，其他实例与命令行（seed、post）写入的文章
+//	靠定期检查发现：文章数或最新的 updated_at 与索引不一致时重新扫描。
+type SitemapService struct {
+	posts      repository.PostRepository
+	baseURL    string
+	postPath   string        // 文章页面的路径模板，{id} 替换为文章 ID
+	authorPath string        // 作者页面的路径模板，为空时不输出作者页面
+	refresh    time.Duration // 检查文章表是否变化的间隔，0 表示不检查
+
+	mu        sync.Mutex
+	loaded    bool
+	checkedAt time.Time // 上次扫描或检查的时间
+	entries   map[uint]sitemapPost
+	rendered  []sitemapURL // 缓存的 URL 列表，nil 表示需要重新生成
+}
+
+// NewSitemapService postPath、authorPath 为站点上实际可访问的页面路径（如 /api/v1/posts/{id}），
+// 拼接在 baseURL 之后作为 <loc>
+func NewSitemapService(posts repository.PostRepository, baseURL, postPath, authorPath string) *SitemapService {
+	return &SitemapService{
+		posts:      posts,
+		baseURL:    baseURL,
+		postPath:   postPath,
+		authorPath: authorPath,
+		entries:    make(map[uint]sitemapPost),
+	}
+}
+
+// SetRefresh 设置检查文章表是否变化的间隔；需在处理请求前调用
+func (s *SitemapService) SetRefresh(d time.Duration) {
+	s.refresh = d
+}
+
+func (s *SitemapService) BaseURL() string {
+	return s.baseURL
+}
+
+// PostSaved 实现 PostObserver
+func (s *SitemapService) PostSaved(post *domain.Post) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		return // 尚未建立索引，首次加载时会读到最新数据
+	}
+	s.entries[post.ID] = sitemapPost{authorID: post.UserID, updatedAt: post.UpdatedAt}
+	s.rendered = nil
+}
+
+// PostDeleted 实现 PostObserver
+func (s *SitemapService) PostDeleted(postID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		return
+	}
+	delete(s.entries, postID)
+	s.rendered = nil
+}
+
+// Index 返回 /sitemap.xml 的内容：
+// URL 数量不超过 SitemapMaxURLs 时直接返回 urlset，否则返回指向分页文件的 sitemapindex
+func (s *SitemapService) Index(ctx context.Context) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "SitemapService.Index")
+	defer span.End()
+
+	urls, err := s.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) <= SitemapMaxURLs {
+		return encodeSitemap(sitemapURLSet{Xmlns: sitemapXmlns, URLs: urls})
+	}
+
+	pages := (len(urls) + SitemapMaxURLs - 1) / SitemapMaxURLs
+	index := sitemapIndex{Xmlns: sitemapXmlns}
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     fmt.Sprintf("%s/sitemaps/%d.xml", s.baseURL, page),
+			LastMod: latestLastMod(pageOf(urls, page)),
+		})
+	}
+	return encodeSitemap(index)
+}
+
+// Page 返回第 page 个分页 sitemap（从 1 开始）
+func (s *SitemapService) Page(ctx context.Context, page int) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "SitemapService.Page")
+	defer span.End()
+
+	urls, err := s.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chunk := pageOf(urls, page)
+	if chunk == nil {
+		return nil, ErrSitemapPageNotFound
+	}
+	return encodeSitemap(sitemapURLSet{Xmlns: sitemapXmlns, URLs: chunk})
+}
+
+const sitemapXmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+func (s *SitemapService) urls(ctx context.Context) ([]sitemapURL, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		if err := s.load(ctx); err != nil {
+			return nil, err
+		}
+	} else if s.refresh > 0 && time.Since(s.checkedAt) >= s.refresh {
+		if err := s.check(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if s.rendered == nil {
+		s.rendered = s.render()
+	}
+	return s.rendered, nil
+}
+
+// load 全表扫描建立索引，在首次访问与 check 发现文章表变化时执行
+func (s *SitemapService) load(ctx context.Context) error {
+	entries := make(map[uint]sitemapPost)
+	err := s.posts.Scan(ctx, 1000, func(batch []domain.Post) error {
+		for _, p := range batch {
+			entries[p.ID] = sitemapPost{authorID: p.UserID, updatedAt: p.UpdatedAt}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.entries, s.rendered = entries, nil
+	s.loaded, s.checkedAt = true, time.Now()
+	return nil
+}
+
+// check 比较文章表与索引的文章数和最新更新时间，不一致时重新扫描。
+// 只查询两个聚合值，比全表扫描便宜；新增、修改会改变最新更新时间，删除会改变文章数
+func (s *SitemapService) check(ctx context.Context) error {
+	count, err := s.posts.Count(ctx)
+	if err != nil {
+		return err
+	}
+	latest, err := s.posts.LastUpdated(ctx)
+	if err != nil {
+		return err
+	}
+
+	var indexed time.Time
+	for _, p := range s.entries {
+		if p.updatedAt.After(indexed) {
+			indexed = p.updatedAt
+		}
+	}
+	if count == int64(len(s.entries)) && latest.Equal(indexed) {
+		s.checkedAt = time.Now()
+		return nil
+	}
+	return s.load(ctx)
+}
+
+// render 生成 URL 列表：先按 ID 排列的文章，再是作者主页（lastmod 取其最新文章的更新时间；未配置作者页面时省略）
+func (s *SitemapService) render() []sitemapURL {
+	postIDs := make([]uint, 0, len(s.entries))
+	authors := make(map[uint]time.Time)
+	for id, p := range s.entries {
+		postIDs = append(postIDs, id)
+		if p.updatedAt.After(authors[p.authorID]) {
+			authors[p.authorID] = p.updatedAt
+		}
+	}
+	sort.Slice(postIDs, func(i, j int) bool { return postIDs[i] < postIDs[j] })
+
+	authorIDs := make([]uint, 0, len(authors))
+	for id := range authors {
+		authorIDs = append(authorIDs, id)
+	}
+	sort.Slice(authorIDs, func(i, j int) bool { return authorIDs[i] < authorIDs[j] })
+
+	if s.authorPath == "" {
+		authorIDs = nil
+	}
+
+	urls := make([]sitemapURL, 0, len(postIDs)+len(authorIDs))
+	for _, id := range postIDs {
+		urls = append(urls, sitemapURL{
+			Loc:     s.loc(s.postPath, id),
+			LastMod: formatLastMod(s.entries[id].updatedAt),
+		})
+	}
+	for _, id := range authorIDs {
+		urls = append(urls, sitemapURL{
+			Loc:     s.loc(s.authorPath, id),
+			LastMod: formatLastMod(authors[id]),
+		})
+	}
+	return urls
+}
+
+// loc 把路径模板中的 {id} 替换为 ID 并拼接站点地址
+func (s *SitemapService) loc(path string, id uint) string {
+	return s.baseURL + strings.ReplaceAll(path, "{id}", strconv.FormatUint(uint64(id), 10))
+}
+
+func pageOf(urls []sitemapURL, page int) []sitemapURL {
+	start := (page - 1) * SitemapMaxURLs
+	if page < 1 || start >= len(urls) {
+		return nil
+	}
+	end := min(start+SitemapMaxURLs, len(urls))
+	return urls[start:end]
+}
+
+// latestLastMod 返回一组 URL 中最新的 lastmod（W3C 时间格式可直接按字符串比较）
+func latestLastMod(urls []sitemapURL) string {
+	var latest string
+	for _, u := range urls {
+		if u.LastMod > latest {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func encodeSitemap(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository/memory"
+	"blogSystem/internal/service"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+const sitemapBase = "https://blog.example.com"
+
+// sitemapEntry urlset 中的 <url> 或 sitemapindex 中的 <sitemap>
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+func parseSitemap(t *testing.T, data []byte, err error) sitemapDoc {
+	t.Helper()
+	must(t, err)
+	var doc sitemapDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid sitemap: %v\n%s", err, data)
+	}
+	return doc
+}
+
+// sitemapLocs 返回 /sitemap.xml（urlset）中的全部 <loc>
+func sitemapLocs(t *testing.T, sm *service.SitemapService) []string {
+	t.Helper()
+	data, err := sm.Index(context.Background())
+	doc := parseSitemap(t, data, err)
+	if doc.XMLName.Local != "urlset" {
+		t.Fatalf("expected a urlset, got %s", doc.XMLName.Local)
+	}
+	locs := make([]string, 0, len(doc.URLs))
+	for _, u := range doc.URLs {
+		locs = append(locs, u.Loc)
+	}
+	return locs
+}
+
+func postLoc(id uint) string   { return fmt.Sprintf("%s/posts/%d", sitemapBase, id) }
+func authorLoc(id uint) string { return fmt.Sprintf("%s/users/%d/posts", sitemapBase, id) }
+
+func newSitemap(s *services, refresh time.Duration) *service.SitemapService {
+	sm := service.NewSitemapService(s.repos.posts, sitemapBase, "/posts/{id}", "/users/{id}/posts")
+	sm.SetRefresh(refresh)
+	s.posts.AddObserver(sm)
+	return sm
+}
+
+func expectLocs(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s: sitemap has %v, want %v", what, got, want)
+	}
+}
+
+// TestSitemap 站点地图索引的增量更新与定期检查
+func TestSitemap(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"Observer", testSitemapObserver},
+		{"Refresh", testSitemapRefresh},
+	})
+}
+
+// testSitemapObserver 建立索引后由 PostObserver 增量更新，不需要重新扫描
+func testSitemapObserver(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	first := createPost(t, s, alice.ID, "first")
+	sm := newSitemap(s, 0)
+	expectLocs(t, "initial scan", sitemapLocs(t, sm), postLoc(first.ID), authorLoc(alice.ID))
+
+	second := createPost(t, s, alice.ID, "second")
+	expectLocs(t, "after create", sitemapLocs(t, sm), postLoc(first.ID), postLoc(second.ID), authorLoc(alice.ID))
+
+	// 文章的 lastmod 取更新时间，作者页面取其最新文章的更新时间
+	first.UpdatedAt = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	sm.PostSaved(first)
+	data, err := sm.Index(ctx)
+	for _, u := range parseSitemap(t, data, err).URLs {
+		if (u.Loc == postLoc(first.ID) || u.Loc == authorLoc(alice.ID)) && u.LastMod != "2030-01-02T03:04:05Z" {
+			t.Errorf("after update: %s lastmod %q, want 2030-01-02T03:04:05Z", u.Loc, u.LastMod)
+		}
+	}
+
+	must(t, s.posts.Delete(ctx, first.ID, alice.ID))
+	expectLocs(t, "after delete", sitemapLocs(t, sm), postLoc(second.ID), authorLoc(alice.ID))
+	must(t, s.posts.Delete(ctx, second.ID, alice.ID))
+	expectLocs(t, "after deleting the author's last post", sitemapLocs(t, sm))
+}
+
+// testSitemapRefresh 其他实例或命令行直接写入数据库的文章，在下一次定期检查时出现在 sitemap 中
+func testSitemapRefresh(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice, bob := createUser(t, s, "alice"), createUser(t, s, "bob")
+	first := createPost(t, s, alice.ID, "first")
+
+	// 跳过 PostService 直接写仓储，模拟另一个实例的写操作
+	external := &domain.Post{Title: "external", Content: "written elsewhere", UserID: alice.ID}
+
+	t.Run("NotBeforeRefresh", func(t *testing.T) {
+		sm := newSitemap(s, time.Hour)
+		expectLocs(t, "initial scan", sitemapLocs(t, sm), postLoc(first.ID), authorLoc(alice.ID))
+		must(t, s.repos.posts.Create(ctx, external))
+		expectLocs(t, "before refresh", sitemapLocs(t, sm), postLoc(first.ID), authorLoc(alice.ID))
+	})
+
+	t.Run("Rescan", func(t *testing.T) {
+		sm := newSitemap(s, time.Millisecond)
+		expectLocs(t, "initial scan", sitemapLocs(t, sm), postLoc(first.ID), postLoc(external.ID), authorLoc(alice.ID))
+
+		byBob := &domain.Post{Title: "by bob", Content: "written elsewhere", UserID: bob.ID}
+		must(t, s.repos.posts.Create(ctx, byBob))
+		time.Sleep(5 * time.Millisecond)
+		expectLocs(t, "after create", sitemapLocs(t, sm),
+			postLoc(first.ID), postLoc(external.ID), postLoc(byBob.ID), authorLoc(alice.ID), authorLoc(bob.ID))
+
+		must(t, s.repos.posts.Delete(ctx, external))
+		must(t, s.repos.posts.Delete(ctx, byBob))
+		time.Sleep(5 * time.Millisecond)
+		expectLocs(t, "after delete", sitemapLocs(t, sm), postLoc(first.ID), authorLoc(alice.ID))
+	})
+}
+
+// TestSitemapIndex 超过 50000 条 URL 时 /sitemap.xml 为索引，分页文件为 /sitemaps/N.xml
+func TestSitemapIndex(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	user := &domain.User{Username: "alice", Password: "x", Email: "alice@example.com"}
+	must(t, store.Users().Create(ctx, user))
+	for i := 0; i < service.SitemapMaxURLs; i++ {
+		must(t, store.Posts().Create(ctx, &domain.Post{Title: "t", Content: "c", UserID: user.ID}))
+	}
+	// 50000 篇文章加 1 个作者页面，需要两个分页
+	sm := service.NewSitemapService(store.Posts(), sitemapBase, "/posts/{id}", "/users/{id}/posts")
+
+	data, err := sm.Index(ctx)
+	index := parseSitemap(t, data, err)
+	if index.XMLName.Local != "sitemapindex" {
+		t.Fatalf("expected a sitemapindex, got %s", index.XMLName.Local)
+	}
+	var locs []string
+	for _, s := range index.Sitemaps {
+		locs = append(locs, s.Loc)
+		if s.LastMod == "" {
+			t.Errorf("%s: missing lastmod", s.Loc)
+		}
+	}
+	expectLocs(t, "index", locs, sitemapBase+"/sitemaps/1.xml", sitemapBase+"/sitemaps/2.xml")
+
+	data, err = sm.Page(ctx, 1)
+	if page := parseSitemap(t, data, err); len(page.URLs) != service.SitemapMaxURLs {
+		t.Errorf("page 1 has %d URLs, want %d", len(page.URLs), service.SitemapMaxURLs)
+	}
+	data, err = sm.Page(ctx, 2)
+	page := parseSitemap(t, data, err)
+	if len(page.URLs) != 1 || page.URLs[0].Loc != authorLoc(user.ID) {
+		t.Errorf("page 2: got %v, want only the author page", page.URLs)
+	}
+	for _, n := range []int{0, 3} {
+		_, err := sm.Page(ctx, n)
+		expectError(t, err, service.ErrSitemapPageNotFound)
+	}
+}
@@ -0,0 +1,37 @@
+package service_test
+
+import (
+	repogorm "blogSystem/internal/repository/gorm"
+	"blogSystem/pkg/database"
+	"context"
+	"testing"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newSQLiteRepos 业务用例使用的 GORM 仓储：内存 SQLite，表结构由嵌入的迁移创建
+func newSQLiteRepos(t *testing.T) repos {
+	db, err := database.Open(database.Options{
+		Driver:       database.DriverSQLite,
+		DSN:          "file::memory:?_pragma=foreign_keys(1)",
+		MaxIdleConns: 1, // 内存库随连接关闭而消失，保持唯一的连接常驻
+		Logger:       gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close(db) })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return repos{
+		users:    repogorm.NewUserRepository(db),
+		posts:    repogorm.NewPostRepository(db),
+		comments: repogorm.NewCommentRepository(db),
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/repository"
+	"blogSystem/pkg/auth"
+	"blogSystem/pkg/tracing"
+	"context"
+	"errors"
+	"time"
+)
+
+// minPasswordLength 与注册接口的校验规则保持一致
+const minPasswordLength = 3
+
+// UserService 用户账号管理：列出用户、调整角色、重置密码、禁用账号，供运维命令行与管理接口使用
+type UserService struct {
+	users repository.UserRepository
+}
+
+func NewUserService(users repository.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+// RequireAdmin 校验用户为未被禁用的管理员
+func (s *UserService) RequireAdmin(ctx context.Context, userID uint) error {
+	ctx, span := tracing.Start(ctx, "UserService.RequireAdmin")
+	defer span.End()
+
+	user, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAdminRequired
+	}
+	if err != nil {
+		return err
+	}
+	if user.Disabled() {
+		return ErrUserDisabled
+	}
+	if user.Role != domain.RoleAdmin {
+		return ErrAdminRequired
+	}
+	return nil
+}
+
+// ValidateToken 作为 auth.Validator 挂在认证中间件上：令牌签名有效之后，
+// 再确认用户仍然存在、未被禁用且令牌版本与当前一致，禁用或重置密码立即生效
+func (s *UserService) ValidateToken(ctx context.Context, claims auth.Claims) error {
+	ctx, span := tracing.Start(ctx, "UserService.ValidateToken")
+	defer span.End()
+
+	user, err := s.users.GetByID(ctx, claims.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return auth.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if user.Disabled() {
+		return ErrUserDisabled
+	}
+	if user.TokenVersion != claims.Version {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
+
+// List 按注册时间倒序分页列出用户
+func (s *UserService) List(ctx context.Context, req PageRequest) (*Page[domain.User], error) {
+	ctx, span := tracing.Start(ctx, "UserService.List")
+	defer span.End()
+
+	return paginate(ctx, req,
+		func(user *domain.User) repository.Cursor {
+			return repository.Cursor{CreatedAt: user.CreatedAt, ID: user.ID}
+		},
+		s.users.List, s.users.Count)
+}
+
+// SetRole 修改用户角色
+func (s *UserService) SetRole(ctx context.Context, username, role string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetRole")
+	defer span.End()
+
+	if !domain.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	return s.update(ctx, username, map[string]interface{}{"role": role})
+}
+
+// ResetPassword 重置用户密码，并使该用户此前签发的令牌全部失效
+func (s *UserService) ResetPassword(ctx context.Context, username, password string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	if len(password) < minPasswordLength {
+		return nil, ErrPasswordTooShort
+	}
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, user, map[string]interface{}{
+		"password":      hashed,
+		"token_version": user.TokenVersion + 1,
+	})
+}
+
+// Disable 禁用账号，禁用后无法再登录，已签发的令牌同时失效；已禁用的账号保持原禁用时间
+func (s *UserService) Disable(ctx context.Context, username string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Disable")
+	defer span.End()
+
+	user, err := s.get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled() {
+		return user, nil
+	}
+	now := time.Now()
+	return s.apply(ctx, user, map[string]interface{}{
+		"disabled_at":   &now,
+		"token_version": user.TokenVersion + 1,
+	})
+}
+
+func (s *UserService) update(ctx context.Context, username string, updates map[string]interface{}) (*domain.User, error) {
+	user, err := s.get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, user, updates)
+}
+
+func (s *UserService) apply(ctx context.Context, user *domain.User, updates map[string]interface{}) (*domain.User, error) {
+	if err := s.users.Update(ctx, user, updates); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserService) get(ctx context.Context, username string) (*domain.User, error) {
+	user, err := s.users.GetByUsername(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
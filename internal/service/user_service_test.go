@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"blogSystem/internal/domain"
+	"blogSystem/internal/service"
+	"blogSystem/pkg/auth"
+	"context"
+	"testing"
+)
+
+// TestUserAdministration 运维命令行与管理接口对账号的操作，以及禁用、重置密码后已签发令牌的失效
+func TestUserAdministration(t *testing.T) {
+	runServiceCases(t, []serviceCase{
+		{"UserAdministration", testUserAdministration},
+		{"DisabledUserCannotLogin", testDisabledUserCannotLogin},
+		{"TokenRevocation", testTokenRevocation},
+	})
+}
+
+func testUserAdministration(t *testing.T, s *services) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+
+	expectError(t, s.users.RequireAdmin(ctx, alice.ID), service.ErrAdminRequired)
+	expectError(t, s.users.RequireAdmin(ctx, 999), service.ErrAdminRequired)
+	_, err := s.users.SetRole(ctx, "alice", "root")
+	expectError(t, err, service.ErrInvalidRole)
+	_, err = s.users.SetRole(ctx, "nobody", domain.RoleAdmin)
+	expectError(t, err, service.ErrUserNotFound)
+	_, err = s.users.ResetPassword(ctx, "alice", "x")
+	expectError(t, err, service.ErrPasswordTooShort)
+
+	_, err = s.users.SetRole(ctx, "alice", domain.RoleAdmin)
+	must(t, err)
+	must(t, s.users.RequireAdmin(ctx, alice.ID))
+
+	_, err = s.users.Disable(ctx, "alice")
+	must(t, err)
+	expectError(t, s.users.RequireAdmin(ctx, alice.ID), service.ErrUserDisabled)
+}
+
+func testDisabledUserCannotLogin(t *testing.T, s *services) {
+	ctx := context.Background()
+	must(t, s.auth.Register(ctx, &domain.User{Username: "alice", Password: "secret", Email: "alice@example.com"}))
+
+	_, err := s.users.Disable(ctx, "alice")
+	must(t, err)
+	_, err = s.auth.Login(ctx, "alice", "secret")
+	expectError(t, err, service.ErrUserDisabled)
+}
+
+func testTokenRevocation(t *testing.T, s *services) {
+	ctx := context.Background()
+	must(t, s.auth.Register(ctx, &domain.User{Username: "alice", Password: "secret", Email: "alice@example.com"}))
+	claims := func(password string) auth.Claims {
+		t.Helper()
+		token, err := s.auth.Login(ctx, "alice", password)
+		must(t, err)
+		c, err := s.tokens.Parse(token)
+		must(t, err)
+		return c
+	}
+
+	before := claims("secret")
+	must(t, s.users.ValidateToken(ctx, before))
+	expectError(t, s.users.ValidateToken(ctx, auth.Claims{UserID: 999}), auth.ErrInvalidToken)
+
+	_, err := s.users.ResetPassword(ctx, "alice", "changed")
+	must(t, err)
+	expectError(t, s.users.ValidateToken(ctx, before), auth.ErrInvalidToken)
+	after := claims("changed")
+	must(t, s.users.ValidateToken(ctx, after))
+
+	_, err = s.users.Disable(ctx, "alice")
+	must(t, err)
+	expectError(t, s.users.ValidateToken(ctx, after), service.ErrUserDisabled)
+}
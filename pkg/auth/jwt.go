@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -9,83 +10,130 @@ import (
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token") // 标准错误定义
-	secretKey       []byte                        // 包内私有的密钥存储
+	ErrInvalidToken = errors.New("invalid token")                 // 标准错误定义
+	ErrMissingToken = errors.New("authorization header required") // 未携带令牌
+	ErrEmptySecret  = errors.New("jwt secret must not be empty")  // 空密钥签发的令牌任何人都能伪造
 )
 
-// 密钥通过 Init() 注入（推荐从环境变量读取）
-// 存储为 []byte 类型（符合 JWT 库要求）
-func Init(key string) {
-	secretKey = []byte(key) // 初始化密钥（需在应用启动时调用）
+// TokenIssuer 签发与校验 JWT，密钥与有效期来自配置（jwt.secret、jwt.lifetime）
+type TokenIssuer struct {
+	secret   []byte // 存储为 []byte 类型（符合 JWT 库要求）
+	lifetime time.Duration
 }
 
-// 令牌生成 (GenerateToken)
+// NewTokenIssuer 密钥为空时返回 ErrEmptySecret
+func NewTokenIssuer(secret string, lifetime time.Duration) (*TokenIssuer, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+	return &TokenIssuer{secret: []byte(secret), lifetime: lifetime}, nil
+}
+
+// Claims 令牌中携带的用户信息。Version 为签发时用户的令牌版本，
+// 重置密码等操作递增版本后，旧令牌即使未过期也会被 Validator 拒绝
+type Claims struct {
+	UserID  uint
+	Version uint
+}
+
+// Validator 在签名校验通过后检查令牌对应的用户当前是否仍然有效（未被禁用、版本未变），
+// 返回的错误交给统一错误处理中间件渲染
+type Validator func(ctx context.Context, claims Claims) error
+
+// Issue 令牌生成
 // JWT 组成：
 // Header：自动生成（指定 HS256 算法）
-// Payload：user_id：业务相关用户标识\exp：过期时间（RFC 7519 标准声明）\iat：签发时间（可选但推荐）
+// Payload：user_id：业务相关用户标识\ver：令牌版本\exp：过期时间（RFC 7519 标准声明）\iat：签发时间（可选但推荐）
 // 签名：使用 HMAC-SHA256 算法 + 密钥生成
 // 返回值："头部.载荷.签名" 格式的字符串
-func GenerateToken(userID uint) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-		"iat":     time.Now().Unix(),
+func (t *TokenIssuer) Issue(claims Claims) (string, error) {
+	now := time.Now()
+	mapClaims := jwt.MapClaims{
+		"user_id": claims.UserID,
+		"ver":     claims.Version,
+		"exp":     now.Add(t.lifetime).Unix(),
+		"iat":     now.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
+	return token.SignedString(t.secret)
 }
 
-// 令牌解析 (ParseToken)
+// Parse 令牌解析
 // 关键步骤：
 // 签名验证：确保令牌未被篡改
 // 算法检查：防止算法替换攻击
-// 声明提取：从 payload 获取 user_id
+// 过期检查：必须携带 exp 声明
+// 声明提取：从 payload 获取 user_id 与 ver（早期签发的令牌没有 ver，按版本 0 处理）
 // 类型转换：处理 JSON 数字到 Go 类型的映射
 
 // 错误处理：
 // 区分令牌无效和解析失败
 // 始终返回标准化错误
-func ParseToken(tokenString string) (uint, error) {
+func (t *TokenIssuer) Parse(tokenString string) (Claims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名算法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return secretKey, nil
-	})
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
 
 	if err != nil {
-		return 0, err
+		return Claims{}, err
 	}
 	// 类型断言提取声明
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		userID, ok := claims["user_id"].(float64)
 		if !ok {
-			return 0, ErrInvalidToken
+			return Claims{}, ErrInvalidToken
+		}
+		var version float64
+		if v, present := claims["ver"]; present {
+			if version, ok = v.(float64); !ok {
+				return Claims{}, ErrInvalidToken
+			}
 		}
-		return uint(userID), nil
+		return Claims{UserID: uint(userID), Version: uint(version)}, nil
 	}
 
-	return 0, ErrInvalidToken
+	return Claims{}, ErrInvalidToken
 }
 
-func JWTMiddleware() gin.HandlerFunc {
+// JWTMiddleware 必须认证：未携带或令牌无效时返回 401；
+// validate 拒绝的令牌（账号已禁用、密码已重置）按其返回的错误中止请求
+func (t *TokenIssuer) JWTMiddleware(validate Validator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		//logger.Info("JWTMiddleware START", zap.String("token", c.GetHeader("Authorization")))
 		tokenString := c.GetHeader("Authorization")
+		// 错误交给统一错误处理中间件渲染为 401
 		if tokenString == "" {
-			c.AbortWithStatusJSON(401, gin.H{"error": "authorization header required"})
+			_ = c.Error(ErrMissingToken)
+			c.Abort()
 			return
 		}
 
-		userID, err := ParseToken(tokenString)
+		claims, err := t.Parse(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(401, gin.H{"error": "invalid token"})
+			_ = c.Error(ErrInvalidToken)
+			c.Abort()
 			return
 		}
+		if err := validate(c.Request.Context(), claims); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Next()
+	}
+}
 
-		c.Set("userID", userID)
+// OptionalJWTMiddleware 可选认证：携带有效令牌时设置 userID，
+// 未携带、令牌无效或被 validate 拒绝时按匿名访客放行，由后续处理器决定返回内容
+func (t *TokenIssuer) OptionalJWTMiddleware(validate Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokenString := c.GetHeader("Authorization"); tokenString != "" {
+			if claims, err := t.Parse(tokenString); err == nil && validate(c.Request.Context(), claims) == nil {
+				c.Set("userID", claims.UserID)
+			}
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,157 @@
+// Package cache 读穿透缓存：Cache 保存序列化后的值，单实例使用 LRU 内存缓存，多实例部署使用 Redis 共享；
+// Store 在其上提供 JSON 编解码、singleflight 合并并发加载与基于版本号的批量失效
+package cache
+
+import (
+	"blogSystem/pkg/logger"
+	"blogSystem/pkg/metrics"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache 缓存存储；ttl 为 0 表示不过期（仍可能被 LRU 淘汰）
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Store 读穿透缓存；nil 的 *Store 表示未启用缓存，GetOrLoad 直接加载，Delete 与 Bump 不做任何事。
+// 缓存不可用时记录日志后回落到直接加载，不影响请求
+type Store struct {
+	cache   Cache
+	ttl     time.Duration
+	metrics *metrics.Metrics
+	group   singleflight.Group
+}
+
+// NewStore m 记录命中与未命中次数，为 nil 时不记录
+func NewStore(c Cache, ttl time.Duration, m *metrics.Metrics) *Store {
+	return &Store{cache: c, ttl: ttl, metrics: m}
+}
+
+// GetOrLoad 按 key 读取缓存，未命中时调用 load 并写入缓存；同一 key 的并发未命中只加载一次。
+// name 为指标与日志中的缓存名称（如 post），load 返回错误时不缓存
+func GetOrLoad[T any](ctx context.Context, s *Store, name, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if s == nil {
+		return load(ctx)
+	}
+
+	var value T
+	if data, ok, err := s.cache.Get(ctx, key); err != nil {
+		logger.Ctx(ctx).Warn("Cache read failed", zap.String("cache", name), zap.String("key", key), zap.Error(err))
+	} else if ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			s.metrics.CacheRequest(name, metrics.CacheHit)
+			return value, nil
+		}
+		// 结构变化后的旧数据无法解码，按未命中处理并覆盖
+	}
+	s.metrics.CacheRequest(name, metrics.CacheMiss)
+
+	// 加载与写入缓存由第一个请求完成，其余请求等待同一结果；
+	// 使用不随该请求取消的 context，避免第一个请求断开导致其余请求一起失败
+	v, err, _ := s.group.Do(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		generation, ok := s.generation(loadCtx, key)
+		value, err := load(loadCtx)
+		if err != nil {
+			return value, err
+		}
+		if !ok {
+			return value, nil
+		}
+		if data, err := json.Marshal(value); err != nil {
+			logger.Ctx(ctx).Warn("Cache encode failed", zap.String("cache", name), zap.Error(err))
+		} else if err := s.cache.Set(loadCtx, key, data, s.ttl); err != nil {
+			logger.Ctx(ctx).Warn("Cache write failed", zap.String("cache", name), zap.String("key", key), zap.Error(err))
+		} else if !s.current(loadCtx, key, generation) {
+			// 加载期间 key 被 Delete，value 可能是写操作之前读到的旧数据，删除刚写入的缓存项
+			if err := s.cache.Delete(loadCtx, key); err != nil {
+				logger.Ctx(ctx).Warn("Cache delete failed", zap.Strings("keys", []string{key}), zap.Error(err))
+			}
+		}
+		return value, nil
+	})
+	if err != nil {
+		return value, err
+	}
+	return v.(T), nil
+}
+
+// Delete 删除缓存项，写操作成功后调用。先更换各 key 的代数再删除，
+// 正在进行的加载写入缓存后发现代数已变化会删除自己写入的旧值（见 GetOrLoad）
+func (s *Store) Delete(ctx context.Context, keys ...string) {
+	if s == nil {
+		return
+	}
+	for _, key := range keys {
+		s.group.Forget(key)
+		s.bump(ctx, generationKey(key), s.ttl)
+	}
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		logger.Ctx(ctx).Warn("Cache delete failed", zap.Strings("keys", keys), zap.Error(err))
+	}
+}
+
+// Version 返回名为 name 的版本号，用作一组缓存键的一部分（如各页文章列表）；
+// 版本号不存在（首次使用或被淘汰）时生成新值，保证不会复用失效前的缓存
+func (s *Store) Version(ctx context.Context, name string) string {
+	if s == nil {
+		return ""
+	}
+	key := "version:" + name
+	if data, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		return string(data)
+	}
+	return s.bump(ctx, key, 0)
+}
+
+// Bump 更换 name 的版本号，使用旧版本号的缓存项全部失效，之后随 TTL 过期或被淘汰
+func (s *Store) Bump(ctx context.Context, name string) {
+	if s == nil {
+		return
+	}
+	s.bump(ctx, "version:"+name, 0)
+}
+
+func (s *Store) bump(ctx context.Context, key string, ttl time.Duration) string {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := s.cache.Set(ctx, key, []byte(version), ttl); err != nil {
+		logger.Ctx(ctx).Warn("Cache version update failed", zap.String("key", key), zap.Error(err))
+	}
+	return version
+}
+
+// generationKey 缓存项 key 的代数，每次 Delete 时更换
+func generationKey(key string) string {
+	return "generation:" + key
+}
+
+// generation 加载前读取 key 的代数，不存在时生成新值（与 Version 相同，被淘汰后不会与删除前的值相同）；
+// 读写失败时 ok 为 false，本次加载的结果不写入缓存
+func (s *Store) generation(ctx context.Context, key string) (string, bool) {
+	data, ok, err := s.cache.Get(ctx, generationKey(key))
+	if err != nil {
+		return "", false
+	}
+	if ok {
+		return string(data), true
+	}
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := s.cache.Set(ctx, generationKey(key), []byte(generation), s.ttl); err != nil {
+		return "", false
+	}
+	return generation, true
+}
+
+// current 写入缓存后检查 key 的代数是否仍为加载前读到的值；过期、被淘汰或读取失败都视为已变化
+func (s *Store) current(ctx context.Context, key, generation string) bool {
+	data, ok, err := s.cache.Get(ctx, generationKey(key))
+	return err == nil && ok && string(data) == generation
+}
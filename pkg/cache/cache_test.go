@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// clock 测试用的可控时钟
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// newCache 返回一个空的缓存与让其时间前进的函数
+type newCache func(t *testing.T) (Cache, func(time.Duration))
+
+// counter 记录 load 被调用的次数
+type counter struct{ n atomic.Int32 }
+
+func (c *counter) load(value string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		c.n.Add(1)
+		return value, nil
+	}
+}
+
+func get(t *testing.T, s *Store, key string, load func(context.Context) (string, error)) string {
+	t.Helper()
+	value, err := GetOrLoad(context.Background(), s, "test", key, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// runStoreSuite 内存与 Redis 缓存共用的 Store 测试
+func runStoreSuite(t *testing.T, newCache newCache) {
+	t.Run("LoadOnce", func(t *testing.T) {
+		c, _ := newCache(t)
+		s := NewStore(c, time.Minute, nil)
+		var loads counter
+		for i := 0; i < 3; i++ {
+			if got := get(t, s, "post:1", loads.load("v1")); got != "v1" {
+				t.Fatalf("got %q, want v1", got)
+			}
+		}
+		if n := loads.n.Load(); n != 1 {
+			t.Fatalf("load called %d times, want 1", n)
+		}
+	})
+
+	t.Run("TTLExpiry", func(t *testing.T) {
+		c, advance := newCache(t)
+		s := NewStore(c, time.Minute, nil)
+		var loads counter
+		get(t, s, "post:1", loads.load("v1"))
+		advance(59 * time.Second)
+		get(t, s, "post:1", loads.load("v2"))
+		if n := loads.n.Load(); n != 1 {
+			t.Fatalf("entry expired before its TTL: %d loads", n)
+		}
+		advance(2 * time.Second)
+		if got := get(t, s, "post:1", loads.load("v2")); got != "v2" || loads.n.Load() != 2 {
+			t.Fatalf("expired entry must be reloaded, got %q after %d loads", got, loads.n.Load())
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c, _ := newCache(t)
+		s := NewStore(c, time.Minute, nil)
+		get(t, s, "post:1", (&counter{}).load("v1"))
+		s.Delete(context.Background(), "post:1")
+		if got := get(t, s, "post:1", (&counter{}).load("v2")); got != "v2" {
+			t.Fatalf("got %q after Delete, want v2", got)
+		}
+	})
+
+	t.Run("BumpInvalidatesListPages", func(t *testing.T) {
+		c, _ := newCache(t)
+		s := NewStore(c, time.Minute, nil)
+		ctx := context.Background()
+		page := func(n int) string { return "posts:list:" + s.Version(ctx, "posts") + fmt.Sprintf(":%d", n) }
+
+		var loads counter
+		for _, n := range []int{1, 2, 1, 2} {
+			get(t, s, page(n), loads.load("before"))
+		}
+		if n := loads.n.Load(); n != 2 {
+			t.Fatalf("expected each page to load once, got %d loads", n)
+		}
+		s.Bump(ctx, "posts")
+		for _, n := range []int{1, 2} {
+			if got := get(t, s, page(n), loads.load("after")); got != "after" {
+				t.Errorf("page %d after Bump: got %q, want after", n, got)
+			}
+		}
+		if n := loads.n.Load(); n != 4 {
+			t.Fatalf("expected both pages to reload after Bump, got %d loads", n)
+		}
+	})
+
+	t.Run("SingleflightCollapse", func(t *testing.T) {
+		c, _ := newCache(t)
+		const callers = 10
+		counting := &countingCache{Cache: c, key: "post:1"}
+		counting.wg.Add(callers)
+		s := NewStore(counting, time.Minute, nil)
+
+		release := make(chan struct{})
+		var loads atomic.Int32
+		load := func(context.Context) (string, error) {
+			loads.Add(1)
+			<-release
+			return "v1", nil
+		}
+
+		var wg sync.WaitGroup
+		results := make(chan string, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, _ := GetOrLoad(context.Background(), s, "test", "post:1", load)
+				results <- value
+			}()
+		}
+		// 所有请求都已未命中缓存后再让加载完成
+		counting.wg.Wait()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(results)
+
+		for value := range results {
+			if value != "v1" {
+				t.Errorf("got %q, want v1", value)
+			}
+		}
+		if n := loads.Load(); n != 1 {
+			t.Fatalf("concurrent misses loaded %d times, want 1", n)
+		}
+	})
+
+	t.Run("DeleteDuringLoad", func(t *testing.T) {
+		c, _ := newCache(t)
+		s := NewStore(c, time.Minute, nil)
+		ctx := context.Background()
+
+		// 加载读到写操作之前的数据，返回前写操作完成并删除缓存
+		stale := func(context.Context) (string, error) {
+			s.Delete(ctx, "post:1")
+			return "stale", nil
+		}
+		if got := get(t, s, "post:1", stale); got != "stale" {
+			t.Fatalf("got %q, want the loaded value", got)
+		}
+		if got := get(t, s, "post:1", (&counter{}).load("fresh")); got != "fresh" {
+			t.Fatalf("a load that raced with Delete was cached: got %q, want fresh", got)
+		}
+		if got := get(t, s, "post:1", (&counter{}).load("unused")); got != "fresh" {
+			t.Fatalf("loads without a concurrent Delete must still be cached: got %q", got)
+		}
+	})
+}
+
+// countingCache 每个请求读取 key 时计数一次，用于等待所有并发请求都已未命中
+type countingCache struct {
+	Cache
+	key string
+	wg  sync.WaitGroup
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, ok, err := c.Cache.Get(ctx, key)
+	if key == c.key {
+		c.wg.Done()
+	}
+	return data, ok, err
+}
+
+func TestMemory(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T) (Cache, func(time.Duration)) {
+		c := &clock{t: time.Unix(1_700_000_000, 0)}
+		m := NewMemory(100)
+		m.now = c.now
+		return m, c.advance
+	})
+
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		ctx := context.Background()
+		m := NewMemory(2)
+		_ = m.Set(ctx, "a", []byte("1"), 0)
+		_ = m.Set(ctx, "b", []byte("2"), 0)
+		if _, ok, _ := m.Get(ctx, "a"); !ok {
+			t.Fatal("a must be cached")
+		}
+		_ = m.Set(ctx, "c", []byte("3"), 0)
+
+		if _, ok, _ := m.Get(ctx, "b"); ok {
+			t.Error("b is the least recently used entry and must be evicted")
+		}
+		for _, key := range []string{"a", "c"} {
+			if _, ok, _ := m.Get(ctx, key); !ok {
+				t.Errorf("%s must still be cached", key)
+			}
+		}
+		if len(m.items) != 2 || m.order.Len() != 2 {
+			t.Fatalf("expected 2 entries, got %d/%d", len(m.items), m.order.Len())
+		}
+	})
+}
+
+// newRedis 连接到一个只属于当前测试的 miniredis
+func newRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedis(client, "test:"), server
+}
+
+func TestRedis(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T) (Cache, func(time.Duration)) {
+		r, server := newRedis(t)
+		return r, server.FastForward
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		r, server := newRedis(t)
+		if err := r.Set(context.Background(), "post:1", []byte("v1"), time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		if !server.Exists("test:post:1") {
+			t.Fatalf("expected the key to be prefixed, got %v", server.Keys())
+		}
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		r, server := newRedis(t)
+		server.Close()
+		s := NewStore(r, time.Minute, nil)
+		var loads counter
+		for i := 0; i < 2; i++ {
+			if got := get(t, s, "post:1", loads.load("v1")); got != "v1" {
+				t.Fatalf("got %q, want the loaded value", got)
+			}
+		}
+		if n := loads.n.Load(); n != 2 {
+			t.Fatalf("expected every request to load while redis is down, got %d loads", n)
+		}
+	})
+}
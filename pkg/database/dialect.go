@@ -0,0 +1,60 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 支持的数据库驱动，与 DB_DRIVER 配置取值一致
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// ForUpdate 为查询加行锁（SELECT ... FOR UPDATE），需在事务中使用。
+// SQLite 不支持行锁，写事务本身是串行的，因此直接返回原查询
+func ForUpdate(db *gorm.DB) *gorm.DB {
+	if db.Dialector.Name() == DriverSQLite {
+		return db
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// MatchPosts 为文章查询添加全文检索条件：
+//
+//	MySQL：MATCH ... AGAINST，使用 ngram 分词的 FULLTEXT 索引（支持中文）
+//	PostgreSQL：to_tsvector @@ plainto_tsquery，使用表达式 GIN 索引
+//	索引由迁移脚本 0001_init 创建
+//	SQLite：退化为 LIKE 模糊匹配
+func MatchPosts(db *gorm.DB, query string) *gorm.DB {
+	switch db.Dialector.Name() {
+	case DriverMySQL:
+		return db.Where("MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)", query)
+	case DriverPostgres:
+		return db.Where("to_tsvector('simple', title || ' ' || content) @@ plainto_tsquery('simple', ?)", query)
+	}
+	pattern := "%" + escapeLike(query) + "%"
+	return db.Where("title LIKE ? ESCAPE '\\' OR content LIKE ? ESCAPE '\\'", pattern, pattern)
+}
+
+// ReindexPosts 重建文章的全文检索索引，用于批量导入数据或调整分词配置之后：
+//
+//	MySQL：OPTIMIZE TABLE 重建 InnoDB 表及其 FULLTEXT 索引
+//	PostgreSQL：REINDEX 表达式 GIN 索引
+//	SQLite：REINDEX posts 表上的普通索引
+func ReindexPosts(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case DriverMySQL:
+		return db.Exec("OPTIMIZE TABLE posts").Error
+	case DriverPostgres:
+		return db.Exec("REINDEX INDEX idx_posts_fulltext").Error
+	}
+	return db.Exec("REINDEX posts").Error
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
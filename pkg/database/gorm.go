@@ -1,18 +1,36 @@
 package database
 
 import (
-	"blogSystem/internal/domain"
+	"context"
+	"fmt"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
+// Options 数据库连接与连接池参数
+type Options struct {
+	Driver          string // mysql（默认）、postgres 或 sqlite（纯 Go 实现，无需 cgo）
+	DSN             string
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration        // 0 表示不限制
+	ConnMaxIdleTime time.Duration        // 0 表示不限制
+	Logger          gormlogger.Interface // GORM 日志适配器，见 NewLogger
+}
+
+// Open 按 opts 打开数据库并设置连接池；调用方负责 Close
+func Open(opts Options) (*gorm.DB, error) {
+	dialector, err := openDialector(opts.Driver, opts.DSN)
+	if err != nil {
+		return nil, err
+	}
 
-func Init(dsn string) error {
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		// 		a) PrepareStmt: true
 		// 启用预处理语句（Prepared Statement）缓存
 		// 作用：
@@ -26,43 +44,56 @@ func Init(dsn string) error {
 		// 设置为 true 后：写操作不再自动使用事务，需要手动管理事务提高性能（减少事务开销）;适合简单操作或需要自己控制事务的场景
 		PrepareStmt:            true,
 		SkipDefaultTransaction: true,
+		Logger:                 opts.Logger,
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	sqlDB, err := DB.DB() // 用于获取这个底层连接池对象
+	sqlDB, err := db.DB() // 用于获取这个底层连接池对象
 
 	if err != nil {
-		return err
+		return nil, err
 	}
-	sqlDB.SetMaxIdleConns(10)                  // 设置连接池中最大空闲连接数（默认值通常为 2）
-	sqlDB.SetMaxOpenConns(100)                 // 设置最大打开连接数（默认无限制）
-	sqlDB.SetConnMaxLifetime(time.Hour)        // 设置连接的最大存活时间（默认无限制）
-	sqlDB.SetConnMaxIdleTime(30 * time.Minute) // 设置连接最大空闲时间
-
-	// 自动迁移
-	if err := DB.AutoMigrate(
-		&domain.User{}, &domain.Post{}, &domain.Comment{},
-	); err != nil {
-		return err
+	sqlDB.SetMaxIdleConns(opts.MaxIdleConns)       // 设置连接池中最大空闲连接数（默认值通常为 2）
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConns)       // 设置最大打开连接数（默认无限制）
+	sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime) // 设置连接的最大存活时间（默认无限制）
+	sqlDB.SetConnMaxIdleTime(opts.ConnMaxIdleTime) // 设置连接最大空闲时间
+	if opts.Driver == DriverSQLite {
+		sqlDB.SetMaxOpenConns(1) // SQLite 同一时间只允许一个写连接，单连接避免 "database is locked"
 	}
-	return nil
+
+	// 表结构由版本化迁移管理（见 migrate.go），不再在启动时 AutoMigrate
+	return db, nil
 }
 
-func Close() error {
-	if DB == nil {
-		return nil
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverMySQL, "":
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
 	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
 
-	sqlDB, err := DB.DB()
+// Close 关闭连接池
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.Close() // 关闭连接池
+	return sqlDB.Close()
 }
 
-func GetDB() *gorm.DB {
-	return DB
+// Ping 检查数据库连接是否可用，用于就绪检查
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
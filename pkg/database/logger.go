@@ -0,0 +1,130 @@
+package database
+
+import (
+	"blogSystem/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger 把 GORM 日志输出到 zap：
+//
+//	info：每条 SQL 以 debug 级别输出（还需 LOG_LEVEL=debug 才会真正打印）
+//	warn：超过慢查询阈值的 SQL 以 warn 级别输出
+//	error：执行出错的 SQL 以 error 级别输出（记录不存在不算错误）
+//
+// SQL 中的参数一律以占位符输出，避免密码哈希、邮箱等敏感数据进入日志。
+type gormLogger struct {
+	log           *zap.Logger // 请求之外（如命令行、后台任务）执行的 SQL 使用该 logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+var _ gormlogger.Interface = (*gormLogger)(nil)
+var _ gorm.ParamsFilter = (*gormLogger)(nil)
+
+// NewLogger 创建 GORM 日志适配器；level 取值 silent、error、warn、info，slowThreshold 为 0 时不记录慢查询。
+// 请求中执行的 SQL 使用请求级 logger（带 request_id），其余使用 log
+func NewLogger(log *zap.Logger, level string, slowThreshold time.Duration) (gormlogger.Interface, error) {
+	lv, err := ParseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return &gormLogger{log: log, level: lv, slowThreshold: slowThreshold}, nil
+}
+
+// ParseLogLevel 解析 GORM 日志级别
+func ParseLogLevel(level string) (gormlogger.LogLevel, error) {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent, nil
+	case "error":
+		return gormlogger.Error, nil
+	case "warn":
+		return gormlogger.Warn, nil
+	case "info":
+		return gormlogger.Info, nil
+	}
+	return 0, fmt.Errorf("unknown database log level %q, must be one of: silent, error, warn, info", level)
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logger.FromContext(ctx, l.log).Info(fmt.Sprintf(msg, data...), zap.String("source", callerSource()))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logger.FromContext(ctx, l.log).Warn(fmt.Sprintf(msg, data...), zap.String("source", callerSource()))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logger.FromContext(ctx, l.log).Error(fmt.Sprintf(msg, data...), zap.String("source", callerSource()))
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("elapsed", elapsed),
+			zap.String("source", callerSource()),
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.FromContext(ctx, l.log).Error("SQL error", append(fields(), zap.Error(err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		logger.FromContext(ctx, l.log).Warn("Slow SQL", append(fields(), zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info:
+		log := logger.FromContext(ctx, l.log)
+		if log.Core().Enabled(zap.DebugLevel) {
+			log.Debug("SQL", fields()...)
+		}
+	}
+}
+
+// ParamsFilter 丢弃 SQL 参数，日志中只保留占位符
+func (l *gormLogger) ParamsFilter(_ context.Context, sql string, _ ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+// callerSource 返回发起查询的业务代码位置（文件:行号），跳过 GORM 内部与本适配器的栈帧
+func callerSource() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.File, "gorm.io/") && !strings.HasSuffix(frame.File, "pkg/database/logger.go") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
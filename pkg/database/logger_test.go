@@ -0,0 +1,142 @@
+package database
+
+import (
+	"blogSystem/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+type secretRow struct {
+	ID    uint
+	Email string
+}
+
+// openLogged 打开内存 SQLite，SQL 日志写入返回的 observer
+func openLogged(t *testing.T, slowThreshold time.Duration) (*gorm.DB, *observer.ObservedLogs) {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := NewLogger(zap.New(core), "info", slowThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(Options{Driver: DriverSQLite, DSN: "file::memory:", MaxIdleConns: 1, Logger: l})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+	if err := db.AutoMigrate(&secretRow{}); err != nil {
+		t.Fatal(err)
+	}
+	logs.TakeAll()
+	return db, logs
+}
+
+// sqlEntries 取出并清空目前记录的日志，只返回记录了 SQL 的条目
+func sqlEntries(logs *observer.ObservedLogs) []observer.LoggedEntry {
+	var entries []observer.LoggedEntry
+	for _, e := range logs.TakeAll() {
+		if _, ok := e.ContextMap()["sql"]; ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func TestLoggerRedactsParams(t *testing.T) {
+	db, logs := openLogged(t, 0)
+	if err := db.Create(&secretRow{Email: "alice@example.com"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var row secretRow
+	if err := db.Where("email = ?", "alice@example.com").First(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	entries := sqlEntries(logs)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 SQL entries, got %d", len(entries))
+	}
+	for _, e := range entries {
+		sql := e.ContextMap()["sql"].(string)
+		if strings.Contains(sql, "alice@example.com") {
+			t.Errorf("bound parameter leaked into the log: %s", sql)
+		}
+		if !strings.Contains(sql, "?") {
+			t.Errorf("expected placeholders in %s", sql)
+		}
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	cases := []struct {
+		name          string
+		slowThreshold time.Duration
+		want          zapcore.Level
+		message       string
+	}{
+		{"fast", time.Hour, zapcore.DebugLevel, "SQL"},
+		{"slow threshold disabled", 0, zapcore.DebugLevel, "SQL"},
+		{"slow", time.Nanosecond, zapcore.WarnLevel, "Slow SQL"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, logs := openLogged(t, tc.slowThreshold)
+			var rows []secretRow
+			if err := db.Find(&rows).Error; err != nil {
+				t.Fatal(err)
+			}
+			entries := sqlEntries(logs)
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 SQL entry, got %d", len(entries))
+			}
+			if e := entries[0]; e.Level != tc.want || e.Message != tc.message {
+				t.Errorf("logged %s %q, want %s %q", e.Level, e.Message, tc.want, tc.message)
+			}
+		})
+	}
+}
+
+func TestLoggerErrors(t *testing.T) {
+	db, logs := openLogged(t, 0)
+
+	var row secretRow
+	if err := db.First(&row, 42).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+	if e := sqlEntries(logs); len(e) != 1 || e[0].Level != zapcore.DebugLevel {
+		t.Fatalf("record not found must not be logged as an error: %v", e)
+	}
+
+	if err := db.Exec("SELECT * FROM missing_table").Error; err == nil {
+		t.Fatal("expected an error")
+	}
+	if e := sqlEntries(logs); len(e) != 1 || e[0].Level != zapcore.ErrorLevel || e[0].Message != "SQL error" {
+		t.Fatalf("expected one SQL error entry, got %v", e)
+	}
+}
+
+// TestLoggerUsesRequestLogger 请求中执行的 SQL 使用 context 中的请求级 logger
+func TestLoggerUsesRequestLogger(t *testing.T) {
+	db, logs := openLogged(t, 0)
+	core, requestLogs := observer.New(zapcore.DebugLevel)
+	ctx := logger.WithContext(context.Background(), zap.New(core).With(zap.String("request_id", "r1")))
+
+	var rows []secretRow
+	if err := db.WithContext(ctx).Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if e := sqlEntries(logs); len(e) != 0 {
+		t.Errorf("unexpected entries on the fallback logger: %v", e)
+	}
+	if e := sqlEntries(requestLogs); len(e) != 1 || e[0].ContextMap()["request_id"] != "r1" {
+		t.Fatalf("expected the SQL entry to carry the request id, got %v", e)
+	}
+}
@@ -0,0 +1,408 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// 迁移锁：同一时间只允许一个实例执行迁移，其余实例等待
+const (
+	lockPollInterval = time.Second
+	lockWaitTimeout  = 2 * time.Minute
+)
+
+var ErrMigrationLocked = errors.New("migration lock is held by another process")
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus 迁移执行状态
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator 基于 embed.FS 中编号 SQL 脚本的版本化迁移
+//
+//	已执行的版本记录在 schema_migrations 表中；
+//	schema_migrations_lock 表中的一行充当跨实例的互斥锁，防止多个副本同时迁移。
+//	PostgreSQL 与 SQLite 中每个迁移在事务内执行；MySQL 的 DDL 会隐式提交，无法回滚。
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+	owner      string
+}
+
+// NewMigrator 按数据库方言加载内嵌的迁移脚本
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	dialect := db.Dialector.Name()
+	migrations, err := loadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:         sqlDB,
+		dialect:    dialect,
+		migrations: migrations,
+		owner:      fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}, nil
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		m := migrationName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if m[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mig, mig.Up, true); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 按版本倒序回滚最近 steps 个已执行的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+			}
+			if err := m.run(ctx, conn, mig, mig.Down, false); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status 返回每个迁移的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.ensureTables(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		at, ok := applied[mig.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version: mig.Version, Name: mig.Name, Applied: ok, AppliedAt: at,
+		})
+	}
+	return statuses, nil
+}
+
+// Pending 返回尚未执行的迁移数量
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// ReadyCheck 返回就绪检查：存在未执行的迁移时失败。
+// 迁移不会在运行期间回退，确认全部执行后不再查询数据库
+func (m *Migrator) ReadyCheck() func(ctx context.Context) error {
+	var migrated atomic.Bool
+	return func(ctx context.Context) error {
+		if migrated.Load() {
+			return nil
+		}
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d migration(s) pending", pending)
+		}
+		migrated.Store(true)
+		return nil
+	}
+}
+
+// ForceUnlock 强制释放迁移锁，仅用于持锁进程异常退出后的人工恢复
+func (m *Migrator) ForceUnlock(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "DELETE FROM schema_migrations_lock")
+	return err
+}
+
+// withLock 在固定连接上获取迁移锁后执行 fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.ensureTables(ctx, conn); err != nil {
+		return err
+	}
+	if err := m.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer m.unlock(conn)
+
+	return fn(conn)
+}
+
+func (m *Migrator) ensureTables(ctx context.Context, conn *sql.Conn) error {
+	// 时间统一存 Unix 秒，使同一份 DDL 适用于所有方言
+	for _, ddl := range []string{
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT       NOT NULL PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL,
+			applied_at BIGINT       NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			id        INT          NOT NULL PRIMARY KEY,
+			owner     VARCHAR(255) NOT NULL,
+			locked_at BIGINT       NOT NULL
+		)`,
+	} {
+		if _, err := conn.ExecContext(ctx, ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lock 通过插入主键固定的一行获取锁；行已存在说明其他实例正在迁移，轮询等待
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) error {
+	deadline := time.Now().Add(lockWaitTimeout)
+	for {
+		_, err := conn.ExecContext(ctx, m.rebind("INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, ?, ?)"),
+			m.owner, time.Now().Unix())
+		if err == nil {
+			return nil
+		}
+
+		var holder string
+		var lockedAt int64
+		row := conn.QueryRowContext(ctx, "SELECT owner, locked_at FROM schema_migrations_lock WHERE id = 1")
+		if scanErr := row.Scan(&holder, &lockedAt); errors.Is(scanErr, sql.ErrNoRows) {
+			continue // 锁刚被释放，立即重试
+		} else if scanErr != nil {
+			return err // 插入失败且查不到锁：返回插入时的原始错误
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s since %s", ErrMigrationLocked, holder, time.Unix(lockedAt, 0).Format(time.RFC3339))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+func (m *Migrator) unlock(conn *sql.Conn) {
+	// 使用独立的 context，保证调用方取消后仍能释放锁
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _ = conn.ExecContext(ctx, m.rebind("DELETE FROM schema_migrations_lock WHERE id = 1 AND owner = ?"), m.owner)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version, at int64
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = time.Unix(at, 0)
+	}
+	return applied, rows.Err()
+}
+
+// run 执行一个迁移脚本并更新 schema_migrations
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig Migration, script string, up bool) error {
+	record := func(exec func(ctx context.Context, query string, args ...any) (sql.Result, error)) error {
+		for _, stmt := range splitStatements(script) {
+			if _, err := exec(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		var err error
+		if up {
+			_, err = exec(ctx, m.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+				mig.Version, mig.Name, time.Now().Unix())
+		} else {
+			_, err = exec(ctx, m.rebind("DELETE FROM schema_migrations WHERE version = ?"), mig.Version)
+		}
+		return err
+	}
+
+	// MySQL 的 DDL 会隐式提交事务，直接在连接上执行
+	if m.dialect == DriverMySQL {
+		return record(conn.ExecContext)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := record(tx.ExecContext); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebind 把 ? 占位符转换为当前方言的格式（PostgreSQL 使用 $1、$2…）
+func (m *Migrator) rebind(query string) string {
+	if m.dialect != DriverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// splitStatements 按行尾分号拆分脚本并去掉 -- 注释行；脚本中的字符串字面量不应跨行包含分号
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// openSQLite 打开一个只属于当前测试的内存 SQLite 数据库（单连接，连接关闭后数据即消失）
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := Open(Options{
+		Driver:       DriverSQLite,
+		DSN:          "file::memory:?_pragma=foreign_keys(1)",
+		MaxIdleConns: 1,
+		Logger:       gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+	return db
+}
+
+func TestMigrationsUpDownSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(done) == 0 || len(done) != len(m.migrations) {
+		t.Fatalf("expected all %d migrations to run, ran %d", len(m.migrations), len(done))
+	}
+	if pending, err := m.Pending(ctx); err != nil || pending != 0 {
+		t.Fatalf("expected no pending migrations, got %d (%v)", pending, err)
+	}
+	if err := m.ReadyCheck()(ctx); err != nil {
+		t.Fatalf("ready check after up: %v", err)
+	}
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("second up must be a no-op, ran %d (%v)", len(done), err)
+	}
+
+	// 每个迁移的 down 脚本都能执行，回滚后可以重新迁移
+	if _, err := m.Down(ctx, len(m.migrations)); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if db.Migrator().HasTable("posts") {
+		t.Fatal("posts table must be dropped after rolling back every migration")
+	}
+	if pending, _ := m.Pending(ctx); pending != len(m.migrations) {
+		t.Fatalf("expected %d pending migrations after down, got %d", len(m.migrations), pending)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up after down: %v", err)
+	}
+}
+
+// TestMatchPostsSQLite SQLite 以 LIKE 模拟全文检索，关键词中的通配符按字面匹配
+func TestMatchPostsSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Exec("INSERT INTO users (username, password, email) VALUES ('alice', 'x', 'alice@example.com')").Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"100% golang", "1000 golang tips", "under_score", "underXscore"} {
+		if err := db.Exec("INSERT INTO posts (title, content, user_id) VALUES (?, 'body', 1)", title).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string][]string{
+		"golang": {"100% golang", "1000 golang tips"},
+		"100%":   {"100% golang"},
+		"r_s":    {"under_score"},
+	}
+	for query, want := range cases {
+		var titles []string
+		if err := MatchPosts(db.Table("posts"), query).Order("id").Pluck("title", &titles).Error; err != nil {
+			t.Fatalf("match %q: %v", query, err)
+		}
+		if len(titles) != len(want) {
+			t.Fatalf("match %q: expected %v, got %v", query, want, titles)
+		}
+		for i := range want {
+			if titles[i] != want[i] {
+				t.Fatalf("match %q: expected %v, got %v", query, want, titles)
+			}
+		}
+	}
+}
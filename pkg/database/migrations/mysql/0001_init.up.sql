@@ -0,0 +1,55 @@
+-- 初始表结构，与原 AutoMigrate 生成的结构一致；
+-- 使用 IF NOT EXISTS，已由 AutoMigrate 建好表的旧库可以直接执行
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    username   VARCHAR(50)  NOT NULL,
+    password   VARCHAR(100) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_users_username (username),
+    UNIQUE KEY idx_users_email (email),
+    KEY idx_users_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS posts (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    title      VARCHAR(200)    NOT NULL,
+    content    TEXT            NOT NULL,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_posts_user_id (user_id),
+    KEY idx_posts_deleted_at (deleted_at),
+    CONSTRAINT fk_users_posts FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    content    TEXT            NOT NULL,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    post_id    BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_comments_user_id (user_id),
+    KEY idx_comments_post_id (post_id),
+    KEY idx_comments_deleted_at (deleted_at),
+    CONSTRAINT fk_posts_comments FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 全文索引（ngram 分词支持中文）；MySQL 不支持 CREATE INDEX IF NOT EXISTS，按 information_schema 判断
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'posts' AND index_name = 'idx_posts_fulltext') = 0,
+    'CREATE FULLTEXT INDEX idx_posts_fulltext ON posts (title, content) WITH PARSER ngram',
+    'DO 0');
+PREPARE create_fulltext FROM @ddl;
+EXECUTE create_fulltext;
+DEALLOCATE PREPARE create_fulltext;
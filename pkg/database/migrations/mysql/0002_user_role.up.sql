@@ -0,0 +1,4 @@
+-- 用户角色与禁用状态
+ALTER TABLE users
+    ADD COLUMN role        VARCHAR(20) NOT NULL DEFAULT 'user',
+    ADD COLUMN disabled_at DATETIME(3) NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_users_created_at_id ON users;
+DROP INDEX idx_comments_post_id_created_at_id ON comments;
+DROP INDEX idx_posts_created_at_id ON posts;
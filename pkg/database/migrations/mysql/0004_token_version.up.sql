@@ -0,0 +1,2 @@
+-- 令牌版本：重置密码或禁用账号时递增，使已签发的令牌失效
+ALTER TABLE users ADD COLUMN token_version INT UNSIGNED NOT NULL DEFAULT 0;
@@ -0,0 +1,43 @@
+-- 初始表结构，与原 AutoMigrate 生成的结构一致；
+-- 使用 IF NOT EXISTS，已由 AutoMigrate 建好表的旧库可以直接执行
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    username   VARCHAR(50)  NOT NULL,
+    password   VARCHAR(100) NOT NULL,
+    email      VARCHAR(100) NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    title      VARCHAR(200) NOT NULL,
+    content    TEXT         NOT NULL,
+    user_id    BIGINT       NOT NULL,
+    CONSTRAINT fk_users_posts FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_posts_user_id ON posts (user_id);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_posts_fulltext ON posts USING GIN (to_tsvector('simple', title || ' ' || content));
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    content    TEXT   NOT NULL,
+    user_id    BIGINT NOT NULL,
+    post_id    BIGINT NOT NULL,
+    CONSTRAINT fk_posts_comments FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments (post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments (deleted_at);
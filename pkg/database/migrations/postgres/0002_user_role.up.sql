@@ -0,0 +1,3 @@
+-- 用户角色与禁用状态
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_comments_post_id_created_at_id;
+DROP INDEX IF EXISTS idx_posts_created_at_id;
@@ -0,0 +1,3 @@
+-- 需要 SQLite 3.35+ 的 DROP COLUMN
+ALTER TABLE users DROP COLUMN disabled_at;
+ALTER TABLE users DROP COLUMN role;
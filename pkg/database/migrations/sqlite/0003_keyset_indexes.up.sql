@@ -0,0 +1,4 @@
+-- 列表按 (created_at, id) 键集分页所需的索引
+CREATE INDEX IF NOT EXISTS idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id_created_at_id ON comments (post_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users (created_at, id);
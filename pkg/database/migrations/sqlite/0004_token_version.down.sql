@@ -0,0 +1,2 @@
+-- 需要 SQLite 3.35+ 的 DROP COLUMN
+ALTER TABLE users DROP COLUMN token_version;
@@ -0,0 +1,127 @@
+// Package health 健康检查注册表：各子系统注册就绪检查，由 /readyz 统一执行
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 检查状态
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// ErrDraining 服务正在优雅关闭，不再接收新流量
+var ErrDraining = errors.New("server is draining")
+
+// Check 就绪检查，返回 nil 表示正常
+type Check func(ctx context.Context) error
+
+// Result 单个检查的结果
+type Result struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report 全部检查的汇总结果
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// OK 所有检查是否都通过
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Registry 就绪检查注册表，可在运行期间并发注册与执行
+type Registry struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checks   map[string]Check
+	draining atomic.Bool
+}
+
+// NewRegistry timeout 为单个检查的最长执行时间，避免某个依赖卡住导致探针超时
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Register 注册检查；同名检查会被覆盖
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// SetDraining 标记服务进入优雅关闭阶段，此后就绪检查始终失败，让负载均衡摘除本实例
+func (r *Registry) SetDraining() {
+	r.draining.Store(true)
+}
+
+// Check 并发执行所有检查并汇总结果
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, checks[i], r.timeout)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(names)+1)}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	if r.draining.Load() {
+		report.Checks["draining"] = Result{Status: StatusUnavailable, Error: ErrDraining.Error()}
+		report.Status = StatusUnavailable
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 检查本身不响应 ctx 时也能按时返回；超时的检查在后台自行结束
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := Result{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	"golang.org/x/text/language"
+)
+
+// 支持的语言，第一个为默认语言（用户以中文为主）
+const (
+	LocaleZH = "zh-CN"
+	LocaleEN = "en"
+)
+
+var (
+	supported = []language.Tag{language.SimplifiedChinese, language.English}
+	locales   = []string{LocaleZH, LocaleEN}
+	matcher   = language.NewMatcher(supported)
+
+	uni = ut.New(zh.New(), zh.New(), en.New())
+)
+
+// Negotiate 根据 Accept-Language 头选择语言，无法匹配时返回默认语言
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return LocaleZH
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return LocaleZH
+	}
+	return locales[index]
+}
+
+// Translator 返回指定语言的校验错误翻译器
+func Translator(locale string) ut.Translator {
+	if locale == LocaleEN {
+		trans, _ := uni.GetTranslator("en")
+		return trans
+	}
+	trans, _ := uni.GetTranslator("zh")
+	return trans
+}
+
+// RegisterValidator 为 validator 注册中英文翻译，覆盖处理器中用到的所有 binding 标签
+// （required、min、max、email 等），应用启动时调用一次
+func RegisterValidator(v *validator.Validate) error {
+	if err := zhTranslations.RegisterDefaultTranslations(v, Translator(LocaleZH)); err != nil {
+		return err
+	}
+	return enTranslations.RegisterDefaultTranslations(v, Translator(LocaleEN))
+}
+
+// Message 按错误码返回本地化的错误描述，没有对应翻译时返回 fallback（英文原文）
+func Message(locale, code, fallback string) string {
+	if msg, ok := messages[locale][code]; ok {
+		return msg
+	}
+	return fallback
+}
@@ -0,0 +1,39 @@
+package i18n
+
+// messages 错误码对应的本地化描述；英文直接使用错误自带的 Message，无需重复登记
+var messages = map[string]map[string]string{
+	LocaleZH: {
+		// 通用
+		"validation_failed":       "请求参数校验失败",
+		"malformed_request":       "请求体格式错误，无法解析",
+		"invalid_id":              "无效的 ID",
+		"route_not_found":         "请求的路径不存在",
+		"internal_error":          "服务器内部错误",
+		"authentication_required": "缺少 Authorization 请求头",
+		"invalid_token":           "令牌无效或已过期",
+		"metrics_forbidden":       "无权访问监控指标",
+		"rate_limited":            "请求过于频繁，请稍后再试",
+		"csrf_token_invalid":      "缺少 CSRF 令牌或令牌无效，请刷新页面后重试",
+		"invalid_cursor":          "分页游标无效，请从第一页重新获取",
+
+		// 用户
+		"username_taken":      "用户名已存在",
+		"email_taken":         "邮箱已被注册",
+		"invalid_credentials": "用户名或密码错误",
+		"user_not_found":      "用户不存在",
+		"user_disabled":       "账号已被禁用",
+		"invalid_role":        "角色只能是 user 或 admin",
+		"password_too_short":  "密码至少需要 3 个字符",
+		"admin_required":      "需要管理员权限",
+		"registration_closed": "暂不开放注册",
+
+		// 文章与评论
+		"post_not_found":         "文章不存在",
+		"post_forbidden":         "只有作者本人才能操作这篇文章",
+		"nothing_to_update":      "没有需要更新的字段",
+		"search_query_required":  "请输入搜索关键词",
+		"comment_not_found":      "评论不存在",
+		"comment_forbidden":      "只有评论者本人才能操作这条评论",
+		"sitemap_page_not_found": "站点地图分页不存在",
+	},
+}
@@ -1,54 +1,143 @@
 package logger
 
 import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 输出目标
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+)
+
+// AccessLogMessage 访问日志的消息文本，开启采样时这条日志总是完整输出
+const AccessLogMessage = "HTTP request"
+
+// 编码格式
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console" // 便于开发时阅读的单行文本格式
 )
 
-var Log *zap.Logger
+// Options 日志配置
+type Options struct {
+	Level  string
+	Format string // json（默认）或 console
+	Output string // stdout（默认）或 file
+
+	// 以下仅在 Output 为 file 时使用，按大小与保留天数轮转
+	File       string
+	MaxSizeMB  int  // 单个文件达到该大小后轮转
+	MaxAgeDays int  // 轮转后的旧文件保留天数，0 表示不按天数清理
+	MaxBackups int  // 最多保留的旧文件个数，0 表示不限
+	Compress   bool // 旧文件是否 gzip 压缩
 
-func Init(logLevel string) {
-	var zapLevel zapcore.Level
-	if err := zapLevel.UnmarshalText([]byte(logLevel)); err != nil {
-		zapLevel = zapcore.InfoLevel // 默认级别
+	// 采样：同一条消息每秒前 SamplingInitial 条全部输出，之后每 SamplingThereafter 条输出一条；
+	// 只对 Info 及以下级别生效，Warn 及以上与访问日志（AccessLogMessage）不采样。SamplingInitial 为 0 时不采样
+	SamplingInitial    int
+	SamplingThereafter int
+}
+
+// New 按配置创建 logger；返回的 level 可在运行时调整（如 PUT /admin/log-level、配置热更新），
+// 立即对该 logger 及其派生的全部 logger 生效
+func New(opts Options) (*zap.Logger, zap.AtomicLevel) {
+	level := zap.NewAtomicLevel()
+	if err := level.UnmarshalText([]byte(opts.Level)); err != nil {
+		level.SetLevel(zapcore.InfoLevel) // 默认级别
 	}
 
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(zapLevel),
-		Development:      false,
-		Encoding:         "json",
-		EncoderConfig:    zap.NewProductionEncoderConfig(),
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	if opts.Format == FormatConsole {
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+		if opts.Output != OutputFile {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
 	}
 
-	var err error
-	Log, err = config.Build()
-	if err != nil {
-		panic(err)
+	var writer zapcore.WriteSyncer = zapcore.Lock(os.Stdout)
+	if opts.Output == OutputFile {
+		writer = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    opts.MaxSizeMB,
+			MaxAge:     opts.MaxAgeDays,
+			MaxBackups: opts.MaxBackups,
+			Compress:   opts.Compress,
+			LocalTime:  true,
+		})
 	}
 
-	zap.ReplaceGlobals(Log)
+	core := zapcore.NewCore(encoder, writer, level)
+	if opts.SamplingInitial > 0 {
+		core = &sampledCore{
+			Core:    core,
+			sampled: zapcore.NewSamplerWithOptions(core, time.Second, opts.SamplingInitial, opts.SamplingThereafter),
+		}
+	}
+
+	log := zap.New(core,
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	)
+	return log, level
 }
 
-func Sync() {
-	_ = Log.Sync()
+// sampledCore 只让 Warn 以下的普通日志经过采样器，警告、错误与访问日志直接写入，
+// 避免高峰期恰好丢掉需要排查的记录
+type sampledCore struct {
+	zapcore.Core              // 不采样的原始 core
+	sampled      zapcore.Core // 包装了同一个 core 的采样器
 }
 
-// 添加全局快捷方法
-func Fatal(msg string, fields ...zap.Field) {
-	Log.Fatal(msg, fields...)
+func (c *sampledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sampledCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields)}
 }
-func Info(msg string, fields ...zap.Field) {
-	Log.Info(msg, fields...)
+
+func (c *sampledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.WarnLevel || ent.Message == AccessLogMessage {
+		return c.Core.Check(ent, ce)
+	}
+	return c.sampled.Check(ent, ce)
 }
 
-func Error(msg string, fields ...zap.Field) {
-	Log.Error(msg, fields...)
+type ctxKey struct{}
+
+// WithContext 把请求级 logger（如带 request_id 字段）存入 ctx，之后通过 Ctx(ctx) 取回
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
 }
-func Debug(msg string, fields ...zap.Field) {
-	Log.Debug(msg, fields...)
+
+// Ctx 返回 ctx 中的请求级 logger（由 RequestID 中间件存入），并附带当前链路的 trace_id、span_id，
+// 便于把同一请求在各层输出的日志关联起来；ctx 中没有 logger 时返回不输出任何内容的 logger
+func Ctx(ctx context.Context) *zap.Logger {
+	return FromContext(ctx, nop)
 }
-func Warn(msg string, fields ...zap.Field) {
-	Log.Warn(msg, fields...)
+
+// FromContext 与 Ctx 相同，但 ctx 中没有 logger 时返回 fallback，供请求之外也会调用的组件使用
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	l, ok := ctx.Value(ctxKey{}).(*zap.Logger)
+	if !ok {
+		l = fallback
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+	return l.With(
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	)
 }
+
+var nop = zap.NewNop()
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestSamplingKeepsWarningsAndAccessLog 采样只丢弃重复的 info 日志，警告、错误与访问日志全部保留
+func TestSamplingKeepsWarningsAndAccessLog(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(&sampledCore{
+		Core:    core,
+		sampled: zapcore.NewSamplerWithOptions(core, time.Minute, 2, 0),
+	}).With(zap.String("request_id", "r1"))
+
+	for i := 0; i < 10; i++ {
+		log.Info("cache miss")
+		log.Info(AccessLogMessage)
+		log.Warn("slow query")
+		log.Error("query failed")
+	}
+
+	counts := map[string]int{}
+	for _, entry := range logs.All() {
+		counts[entry.Message]++
+		if entry.ContextMap()["request_id"] != "r1" {
+			t.Fatalf("%q lost the request_id field", entry.Message)
+		}
+	}
+	want := map[string]int{"cache miss": 2, AccessLogMessage: 10, "slow query": 10, "query failed": 10}
+	for message, n := range want {
+		if counts[message] != n {
+			t.Errorf("%q: logged %d times, want %d", message, counts[message], n)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package metrics Prometheus 指标：HTTP 请求、数据库查询、连接池与业务事件
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blog"
+
+// 缓存查询结果标签值
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// 登录结果标签值
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+// Metrics 一个应用实例的指标及其注册表，由 app.New 创建后注入中间件、服务与缓存。
+// 不使用 prometheus 默认注册表，避免第三方库的指标混入，同一进程中的多个实例互不冲突。
+// nil 的 *Metrics 表示未启用指标，各记录方法不做任何事
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// HTTP 请求指标，route 为路由模板（如 /api/v1/posts/:id），避免按实际路径产生无限多的标签值
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+
+	// 数据库查询指标，operation 为 create/query/update/delete/row/raw
+	dbQueryDuration *prometheus.HistogramVec
+	dbQueryErrors   *prometheus.CounterVec
+
+	// 业务事件计数
+	postsCreated    prometheus.Counter
+	commentsCreated prometheus.Counter
+	logins          *prometheus.CounterVec
+
+	rateLimited   *prometheus.CounterVec // group 为限流分组（auth、write、read）
+	cacheRequests *prometheus.CounterVec // cache 为缓存名称（如 post、post_list），result 为 hit 或 miss
+}
+
+// New 创建注册表并注册全部指标，包括 Go 运行时与进程指标
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	factory := promauto.With(registry)
+
+	return &Metrics{
+		registry: registry,
+
+		httpRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests processed, by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		dbQueryDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "GORM statement latency, by operation and table.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		dbQueryErrors: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "GORM statements that returned an error other than record not found.",
+		}, []string{"operation", "table"}),
+
+		postsCreated: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_created_total",
+			Help:      "Posts created.",
+		}),
+		commentsCreated: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "comments_created_total",
+			Help:      "Comments created.",
+		}),
+		logins: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts, by result (success or failure).",
+		}, []string{"result"}),
+
+		rateLimited: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Requests rejected with 429 by the rate limiter, by policy group.",
+		}, []string{"group"}),
+		cacheRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Read-through cache lookups, by cache name and result (hit or miss).",
+		}, []string{"cache", "result"}),
+	}
+}
+
+// ObserveRequest 记录一个 HTTP 请求的次数与耗时
+func (m *Metrics) ObserveRequest(method, route, status string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, status).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, status).Observe(elapsed.Seconds())
+}
+
+// ObserveQuery 记录一条 GORM 语句的耗时，failed 表示返回了记录不存在以外的错误
+func (m *Metrics) ObserveQuery(operation, table string, elapsed time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.dbQueryDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+	if failed {
+		m.dbQueryErrors.WithLabelValues(operation, table).Inc()
+	}
+}
+
+// PostCreated 记录一篇新文章
+func (m *Metrics) PostCreated() {
+	if m != nil {
+		m.postsCreated.Inc()
+	}
+}
+
+// CommentCreated 记录一条新评论
+func (m *Metrics) CommentCreated() {
+	if m != nil {
+		m.commentsCreated.Inc()
+	}
+}
+
+// Login 记录一次登录尝试，result 为 LoginSuccess 或 LoginFailure
+func (m *Metrics) Login(result string) {
+	if m != nil {
+		m.logins.WithLabelValues(result).Inc()
+	}
+}
+
+// RateLimited 记录一次被限流拒绝的请求
+func (m *Metrics) RateLimited(group string) {
+	if m != nil {
+		m.rateLimited.WithLabelValues(group).Inc()
+	}
+}
+
+// CacheRequest 记录一次读穿透缓存查询，result 为 CacheHit 或 CacheMiss
+func (m *Metrics) CacheRequest(cache, result string) {
+	if m != nil {
+		m.cacheRequests.WithLabelValues(cache, result).Inc()
+	}
+}
+
+// RegisterDBStats 注册连接池指标（sql.DBStats：打开/使用中/空闲连接数、等待次数等）
+func (m *Metrics) RegisterDBStats(db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler 以 Prometheus 文本格式输出注册表中的指标
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
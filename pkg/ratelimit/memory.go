@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理已装满的桶的间隔；装满的桶与不存在的桶等价，删除后不影响限流结果
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time // 上次更新 tokens 的时间
+	full   time.Time // 不再有请求时桶重新装满的时间
+}
+
+// MemoryStore 进程内的令牌桶，多实例部署时每个实例单独计数
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time // 测试中替换为可控的时钟
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.last), limit)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	r := result(allowed, b.tokens, limit)
+	b.full = now.Add(r.ResetAfter)
+	return r, nil
+}
+
+// sweep 删除已装满的桶，防止不再访问的客户端占用内存
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
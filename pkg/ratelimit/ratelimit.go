@@ -0,0 +1,68 @@
+// Package ratelimit 令牌桶限流：每个键一个桶，容量为 Burst，每秒补充 Rate 个令牌，每个请求消耗一个。
+// 桶的状态保存在 Store 中，单实例使用 MemoryStore，多实例部署使用 RedisStore 共享计数
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit 一个桶的参数；Rate 为 0 表示不限流
+type Limit struct {
+	Rate  float64 // 每秒补充的令牌数
+	Burst int     // 桶容量，即允许的最大突发请求数
+}
+
+// PerPeriod 每 period 允许 requests 个请求、最多突发 burst 个的限额
+func PerPeriod(requests int, period time.Duration, burst int) Limit {
+	if requests <= 0 || period <= 0 {
+		return Limit{}
+	}
+	return Limit{Rate: float64(requests) / period.Seconds(), Burst: burst}
+}
+
+// Unlimited 是否不限流
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Result 一次取令牌的结果，用于输出 X-RateLimit-* 响应头
+type Result struct {
+	Allowed    bool
+	Limit      int           // 桶容量
+	Remaining  int           // 本次之后剩余的令牌数
+	ResetAfter time.Duration // 桶重新装满所需的时间
+	RetryAfter time.Duration // 被拒绝时，下一个令牌可用前需要等待的时间
+}
+
+// Store 保存令牌桶状态；Take 从 key 对应的桶中取一个令牌，必须是原子操作
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// refill 按经过的时间补充令牌，不超过桶容量
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// result 由取令牌后剩余的令牌数计算响应头所需的各项
+func result(allowed bool, tokens float64, limit Limit) Result {
+	r := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return r
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
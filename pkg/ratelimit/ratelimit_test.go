@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// clock 测试用的可控时钟
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestPerPeriod(t *testing.T) {
+	l := PerPeriod(30, time.Minute, 5)
+	if l.Rate != 0.5 || l.Burst != 5 || l.Unlimited() {
+		t.Fatalf("PerPeriod(30, 1m, 5) = %+v", l)
+	}
+	for _, l := range []Limit{PerPeriod(0, time.Minute, 5), PerPeriod(10, 0, 5), PerPeriod(10, time.Minute, 0)} {
+		if !l.Unlimited() {
+			t.Errorf("%+v must be unlimited", l)
+		}
+	}
+}
+
+func TestRefill(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 5}
+	cases := []struct {
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 500 * time.Millisecond, 1},
+		{1.5, 250 * time.Millisecond, 2},
+		{4, time.Hour, 5},    // 不超过桶容量
+		{3, -time.Second, 3}, // 时钟回拨不扣减令牌
+		{0, 0, 0},
+	}
+	for _, c := range cases {
+		if got := refill(c.tokens, c.elapsed, limit); got != c.want {
+			t.Errorf("refill(%v, %v) = %v, want %v", c.tokens, c.elapsed, got, c.want)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	limit := Limit{Rate: 0.5, Burst: 4}
+	allowed := result(true, 2.5, limit)
+	if allowed.Limit != 4 || allowed.Remaining != 2 || allowed.ResetAfter != 3*time.Second || allowed.RetryAfter != 0 {
+		t.Fatalf("allowed result = %+v", allowed)
+	}
+	denied := result(false, 0.25, limit)
+	if denied.Remaining != 0 || denied.RetryAfter != 1500*time.Millisecond || denied.ResetAfter != 7500*time.Millisecond {
+		t.Fatalf("denied result = %+v", denied)
+	}
+}
+
+// runStoreSuite 两种存储应给出相同的限流结果
+func runStoreSuite(t *testing.T, newStore func(t *testing.T, c *clock) Store) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 3} // 每秒 1 个，最多突发 3 个
+
+	t.Run("BurstThenDeny", func(t *testing.T) {
+		c := &clock{t: time.Unix(1_700_000_000, 0)}
+		s := newStore(t, c)
+		for i := 2; i >= 0; i-- {
+			r, err := s.Take(ctx, "k", limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Allowed || r.Remaining != i || r.Limit != 3 {
+				t.Fatalf("request %d: %+v", 3-i, r)
+			}
+		}
+		r, err := s.Take(ctx, "k", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Allowed || r.RetryAfter != time.Second || r.ResetAfter != 3*time.Second {
+			t.Fatalf("request over burst: %+v", r)
+		}
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		c := &clock{t: time.Unix(1_700_000_000, 0)}
+		s := newStore(t, c)
+		for i := 0; i < 3; i++ {
+			if _, err := s.Take(ctx, "k", limit); err != nil {
+				t.Fatal(err)
+			}
+		}
+		c.advance(1500 * time.Millisecond) // 补充 1.5 个
+		r, err := s.Take(ctx, "k", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Allowed || r.Remaining != 0 {
+			t.Fatalf("after 1.5s: %+v", r)
+		}
+		r, _ = s.Take(ctx, "k", limit)
+		if r.Allowed || r.RetryAfter != 500*time.Millisecond {
+			t.Fatalf("half a token left: %+v", r)
+		}
+
+		c.advance(time.Hour) // 远超装满所需时间，仍只有 Burst 个
+		for i := 0; i < 3; i++ {
+			if r, _ := s.Take(ctx, "k", limit); !r.Allowed {
+				t.Fatalf("request %d after a full refill was denied", i+1)
+			}
+		}
+		if r, _ := s.Take(ctx, "k", limit); r.Allowed {
+			t.Fatal("refill must not exceed the burst")
+		}
+	})
+
+	t.Run("KeysAreIsolated", func(t *testing.T) {
+		c := &clock{t: time.Unix(1_700_000_000, 0)}
+		s := newStore(t, c)
+		for i := 0; i < 4; i++ {
+			_, _ = s.Take(ctx, "write:user:1", limit)
+		}
+		for _, key := range []string{"write:user:2", "write:ip:192.0.2.1", "read:user:1"} {
+			r, err := s.Take(ctx, key, limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Allowed || r.Remaining != 2 {
+				t.Fatalf("%s shares a bucket with write:user:1: %+v", key, r)
+			}
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T, c *clock) Store {
+		s := NewMemoryStore()
+		s.now = c.now
+		return s
+	})
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	c := &clock{t: time.Unix(1_700_000_000, 0)}
+	s := NewMemoryStore()
+	s.now = c.now
+	limit := Limit{Rate: 1, Burst: 3}
+	for i := 0; i < 10; i++ {
+		_, _ = s.Take(context.Background(), fmt.Sprintf("ip:%d", i), limit)
+	}
+	c.advance(sweepInterval)
+	_, _ = s.Take(context.Background(), "ip:new", limit)
+	if len(s.buckets) != 1 {
+		t.Fatalf("expected refilled buckets to be swept, %d left", len(s.buckets))
+	}
+}
+
+// newRedisStore 连接到一个只属于当前测试的 miniredis
+func newRedisStore(t *testing.T, c *clock) (*RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	s := NewRedisStore(client, "test:")
+	s.now = c.now
+	return s, server
+}
+
+// TestRedisStore 在 miniredis 上执行真实的 Lua 脚本
+func TestRedisStore(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T, c *clock) Store {
+		s, _ := newRedisStore(t, c)
+		return s
+	})
+
+	t.Run("BucketExpires", func(t *testing.T) {
+		s, server := newRedisStore(t, &clock{t: time.Unix(1_700_000_000, 0)})
+		if _, err := s.Take(context.Background(), "k", Limit{Rate: 1, Burst: 3}); err != nil {
+			t.Fatal(err)
+		}
+		// 重新装满需要 1 秒，额外保留 1 秒
+		if ttl := server.TTL("test:k"); ttl != 2*time.Second {
+			t.Fatalf("bucket TTL = %v, want 2s", ttl)
+		}
+		server.FastForward(2 * time.Second)
+		if server.Exists("test:k") {
+			t.Fatal("idle bucket must expire")
+		}
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		s, server := newRedisStore(t, &clock{t: time.Now()})
+		server.Close()
+		if _, err := s.Take(context.Background(), "k", Limit{Rate: 1, Burst: 3}); err == nil {
+			t.Fatal("expected an error when redis is unreachable")
+		}
+	})
+}
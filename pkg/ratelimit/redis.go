@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript 在 Redis 中原子地完成补充与扣减；桶以 hash 保存 tokens 与更新时间（毫秒），
+// 过期时间设为重新装满所需的时长，空闲的桶自动删除。
+// 当前时间由调用方传入，兼容不允许在脚本中调用 TIME 的 Redis 协议实现；各实例的时钟偏差只影响补充速度
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore 保存在 Redis（或兼容 Redis 协议的服务）中的令牌桶，多个实例共享计数
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+	now    func() time.Time // 测试中替换为可控的时钟
+}
+
+// NewRedisStore 键名为 prefix + 调用方传入的 key
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, now: time.Now}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := s.now().UnixMilli()
+	reply, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'g', -1, 64), limit.Burst, now).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: redis: %w", err)
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("ratelimit: unexpected script reply %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	text, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(tokens) {
+		return Result{}, fmt.Errorf("ratelimit: unexpected token count %q", text)
+	}
+	return result(allowed == 1, tokens, limit), nil
+}
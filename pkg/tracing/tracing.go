@@ -0,0 +1,72 @@
+// Package tracing OpenTelemetry 链路追踪：TracerProvider、W3C traceparent 传播与 GORM 插件。
+// 不设置 otel 的全局 TracerProvider：provider 由调用方显式交给 HTTP 中间件，
+// 服务层与 GORM 的 span 沿用请求 span 所属的 provider（见 Start）
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "blogSystem"
+
+// 支持的导出器，与 TRACING_EXPORTER 配置取值一致
+const (
+	ExporterOTLP   = "otlp"   // OTLP/HTTP，端点等由标准的 OTEL_EXPORTER_OTLP_* 环境变量配置
+	ExporterStdout = "stdout" // 输出到标准输出，用于本地调试
+)
+
+// Init 按导出器名称创建导出器与 TracerProvider，返回的 TracerProvider 需在退出前 Shutdown，
+// 以刷新尚未导出的 span
+func Init(ctx context.Context, exporterName, serviceName string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", exporterName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewProvider(sdktrace.NewBatchSpanProcessor(exporter), serviceName, sampleRatio), nil
+}
+
+// NewProvider 使用给定的 span 处理器创建 TracerProvider。
+// 测试中可传入 sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter()) 同步收集 span
+func NewProvider(processor sdktrace.SpanProcessor, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		// 上游已决定采样时沿用上游的决定，否则按比例采样
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+}
+
+// Propagator 解析与注入 W3C traceparent、baggage 请求头
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Start 在 ctx 下开始一个 span，调用方负责 span.End()。
+// span 由 ctx 中上级 span 所属的 TracerProvider 创建；没有上级 span（如命令行、未启用追踪）时
+// 使用 otel 的全局 provider，默认为无操作实现
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	provider := otel.GetTracerProvider()
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		provider = parent.TracerProvider()
+	}
+	return provider.Tracer(instrumentationName).Start(ctx, name, opts...)
+}